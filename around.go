@@ -0,0 +1,69 @@
+package keyset
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrAnchorRequired is returned when an around page has no anchor cursor.
+var ErrAnchorRequired = errors.New("keyset: around page requires an anchor cursor")
+
+// AroundPages splits a page anchored at p.Cursor into the two windows that
+// make up an "around" view: up to p.Limit items before the anchor and the
+// anchor followed by up to p.Limit items after it.
+//
+// Both windows fetch one extra row so that MergeAround can tell whether more
+// items exist beyond either end.
+func AroundPages(p Page) (before, after Page) {
+	p.EnsureDefaults()
+	before = Page{Cursor: p.Cursor, Limit: p.Limit + 1, Dir: DirPrev, Snapshot: p.Snapshot}
+	after = Page{Cursor: p.Cursor, Limit: p.Limit + 2, Dir: DirNext, Bound: Inclusive, Snapshot: p.Snapshot}
+	return before, after
+}
+
+// MergeAround combines the results of the windows returned by AroundPages,
+// both in display order, into a single Result centered on the anchor.
+// The extra rows are trimmed and turned into HasPrev/HasNext. If the anchor
+// row no longer exists (e.g. it was deleted), the page keeps up to p.Limit
+// items on either side of where it was.
+func MergeAround[T any](p Page, before, after []T, cursor CursorFunc[T]) Result[T] {
+	p.EnsureDefaults()
+	hasPrev := len(before) > p.Limit
+	if hasPrev {
+		before = before[len(before)-p.Limit:]
+	}
+	n := p.Limit
+	if len(after) > 0 && cursor(after[0]) == p.Cursor {
+		n++ // The anchor itself
+	}
+	hasNext := len(after) > n
+	if hasNext {
+		after = after[:n]
+	}
+
+	r := newResult(slices.Concat(before, after), cursor)
+	r.HasPrev, r.HasNext = hasPrev, hasNext
+	r.Snapshot = p.Snapshot
+	return r
+}
+
+// Around fetches the items around the anchor p.Cursor (typically the cursor of
+// a specific message in a chat or log viewer) and returns them in display
+// order. The result's PrevCursor and NextCursor continue with DirPrev and
+// DirNext pages from either end.
+func Around[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T]) (Result[T], error) {
+	if p.Cursor == "" {
+		return Result[T]{}, ErrAnchorRequired
+	}
+	bp, ap := AroundPages(p)
+	before, err := fetch(ctx, bp)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	after, err := fetch(ctx, ap)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return MergeAround(p, before, after, cursor), nil
+}
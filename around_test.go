@@ -0,0 +1,97 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestAroundPages(t *testing.T) {
+	t.Parallel()
+
+	cur := keyset.EncodeInt64Cursor(10)
+	before, after := keyset.AroundPages(keyset.Page{Cursor: cur, Limit: 3})
+
+	if before != (keyset.Page{Cursor: cur, Limit: 4, Dir: keyset.DirPrev}) {
+		t.Fatalf("unexpected before page: %+v", before)
+	}
+	if after != (keyset.Page{Cursor: cur, Limit: 5, Dir: keyset.DirNext, Bound: keyset.Inclusive}) {
+		t.Fatalf("unexpected after page: %+v", after)
+	}
+}
+
+func TestAround(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	cases := []struct {
+		name             string
+		anchor           int64
+		want             []int64
+		hasPrev, hasNext bool
+	}{
+		{"middle", 5, []int64{3, 4, 5, 6, 7}, true, true},
+		{"near head", 2, []int64{1, 2, 3, 4}, false, true},
+		{"near tail", 9, []int64{7, 8, 9, 10}, true, false},
+		{"exact fit", 3, []int64{1, 2, 3, 4, 5}, false, true},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(c.anchor), Limit: 2}
+			res, err := keyset.Around(context.Background(), p, memFetch(ids, &calls), keyset.EncodeInt64Cursor)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(res.Items, c.want) {
+				t.Fatalf("items want %v, got %v", c.want, res.Items)
+			}
+			if res.HasPrev != c.hasPrev || res.HasNext != c.hasNext {
+				t.Fatalf("flags want prev=%v next=%v, got prev=%v next=%v", c.hasPrev, c.hasNext, res.HasPrev, res.HasNext)
+			}
+			if res.PrevCursor != keyset.EncodeInt64Cursor(c.want[0]) || res.NextCursor != keyset.EncodeInt64Cursor(c.want[len(c.want)-1]) {
+				t.Fatalf("cursors must point at both ends: %+v", res)
+			}
+		})
+	}
+}
+
+func TestAround_MissingAnchor(t *testing.T) {
+	t.Parallel()
+
+	// The anchor 5 was deleted: the page keeps two items on either side of it.
+	ids := []int64{1, 2, 3, 4, 6, 7, 8, 9, 10}
+	calls := 0
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(5), Limit: 2}
+	res, err := keyset.Around(context.Background(), p, memFetch(ids, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int64{3, 4, 6, 7}; !slices.Equal(res.Items, want) || !res.HasPrev || !res.HasNext {
+		t.Fatalf("want %v with both flags, got %v (prev=%v next=%v)", want, res.Items, res.HasPrev, res.HasNext)
+	}
+
+	p.Cursor = keyset.EncodeInt64Cursor(9)
+	res, err = keyset.Around(context.Background(), p, memFetch([]int64{6, 7, 8, 10, 11}, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int64{7, 8, 10, 11}; !slices.Equal(res.Items, want) || !res.HasPrev || res.HasNext {
+		t.Fatalf("want %v with only HasPrev, got %v (prev=%v next=%v)", want, res.Items, res.HasPrev, res.HasNext)
+	}
+}
+
+func TestAround_RequiresAnchor(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	_, err := keyset.Around(context.Background(), keyset.Page{Limit: 2}, memFetch(nil, &calls), keyset.EncodeInt64Cursor)
+	if !errors.Is(err, keyset.ErrAnchorRequired) || calls != 0 {
+		t.Fatalf("want ErrAnchorRequired without fetching, got %v (calls=%d)", err, calls)
+	}
+}
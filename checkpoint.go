@@ -0,0 +1,165 @@
+package keyset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CheckpointStore persists the last processed cursor of long-running scans,
+// keyed by a caller-chosen scan name.
+type CheckpointStore interface {
+	// Load returns the stored cursor for key, or "" if there is none.
+	Load(ctx context.Context, key string) (string, error)
+	// Save stores cursor for key, replacing any previous value.
+	Save(ctx context.Context, key, cursor string) error
+}
+
+// MemoryCheckpointStore is an in-process CheckpointStore, mainly for tests
+// and for jobs that only need to survive retries within the same process.
+type MemoryCheckpointStore struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{cursors: map[string]string{}}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[key], nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(_ context.Context, key, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors[key] = cursor
+	return nil
+}
+
+// FileCheckpointStore keeps checkpoints in a JSON file mapping keys to cursors.
+// Every Save rewrites the file atomically (write to a temporary file, then rename),
+// so a crash never leaves a torn checkpoint behind.
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore backed by path.
+// The file is created on the first Save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore.
+func (s *FileCheckpointStore) Load(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursors, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return cursors[key], nil
+}
+
+// Save implements CheckpointStore.
+func (s *FileCheckpointStore) Save(_ context.Context, key, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursors, err := s.read()
+	if err != nil {
+		return err
+	}
+	cursors[key] = cursor
+
+	b, err := json.Marshal(cursors)
+	if err != nil {
+		return fmt.Errorf("keyset: encode checkpoints: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("keyset: write checkpoints: %w", err)
+	}
+	// Removing is a no-op after a successful rename.
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("keyset: write checkpoints: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("keyset: write checkpoints: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("keyset: write checkpoints: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("keyset: write checkpoints: %w", err)
+	}
+	return nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]string, error) {
+	cursors := map[string]string{}
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keyset: read checkpoints: %w", err)
+	}
+	if err := json.Unmarshal(b, &cursors); err != nil {
+		return nil, fmt.Errorf("keyset: decode checkpoints: %w", err)
+	}
+	return cursors, nil
+}
+
+// CheckpointWalker iterates pages like Pages but saves the boundary cursor to
+// Store after every page, so a restarted job resumes where it left off.
+type CheckpointWalker[T any] struct {
+	Store  CheckpointStore // Where cursors are persisted
+	Key    string          // Name of the scan within Store
+	Fetch  FetchFunc[T]    // Loads a page
+	Cursor CursorFunc[T]   // Encodes the cursor of an item
+	Page   Page            // Initial page (Limit/Dir); Cursor is used only without a checkpoint
+}
+
+// Walk resumes from the stored cursor (if any) and calls fn for every page.
+// The checkpoint is saved only after fn succeeds, so a page may be handed to fn
+// again after a crash between fn and Save (at-least-once). Use ksql.TxWalker to
+// commit the checkpoint together with the work for exactly-once processing.
+//
+// When the scan is exhausted, the last cursor stays stored: running the walker
+// again only visits rows added after it.
+func (w CheckpointWalker[T]) Walk(ctx context.Context, fn func(ctx context.Context, batch []T) error) error {
+	stored, err := w.Store.Load(ctx, w.Key)
+	if err != nil {
+		return fmt.Errorf("keyset: load checkpoint %q: %w", w.Key, err)
+	}
+	p := w.Page.Resume(stored)
+	p.EnsureDefaults()
+
+	for batch, err := range Pages(ctx, p, w.Fetch, w.Cursor) {
+		if err != nil {
+			return err
+		}
+		next := w.Cursor(BoundaryItem(p, batch))
+		if err := fn(ctx, batch); err != nil {
+			return err
+		}
+		if err := w.Store.Save(ctx, w.Key, next); err != nil {
+			return fmt.Errorf("keyset: save checkpoint %q: %w", w.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestCheckpointStores_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]keyset.CheckpointStore{
+		"memory": keyset.NewMemoryCheckpointStore(),
+		"file":   keyset.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json")),
+	}
+	for name, s := range stores {
+		s := s
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			if got, err := s.Load(ctx, "backfill"); err != nil || got != "" {
+				t.Fatalf("want empty checkpoint, got (%q, %v)", got, err)
+			}
+			if err := s.Save(ctx, "backfill", "a"); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if err := s.Save(ctx, "other", "b"); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if err := s.Save(ctx, "backfill", "c"); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			if got, err := s.Load(ctx, "backfill"); err != nil || got != "c" {
+				t.Fatalf("want c, got (%q, %v)", got, err)
+			}
+			if got, err := s.Load(ctx, "other"); err != nil || got != "b" {
+				t.Fatalf("want b, got (%q, %v)", got, err)
+			}
+		})
+	}
+}
+
+func TestFileCheckpointStore_SurvivesReopen(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+
+	if err := keyset.NewFileCheckpointStore(path).Save(ctx, "job", "cur"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if got, err := keyset.NewFileCheckpointStore(path).Load(ctx, "job"); err != nil || got != "cur" {
+		t.Fatalf("want cur, got (%q, %v)", got, err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("temporary files must be cleaned up, got %v (%v)", entries, err)
+	}
+}
+
+func TestCheckpointWalker_ResumesAfterFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7}
+	calls := 0
+	store := keyset.NewMemoryCheckpointStore()
+	w := keyset.CheckpointWalker[int64]{
+		Store:  store,
+		Key:    "backfill",
+		Fetch:  memFetch(ids, &calls),
+		Cursor: keyset.EncodeInt64Cursor,
+		Page:   keyset.Page{Limit: 2},
+	}
+
+	boom := errors.New("boom")
+	var seen []int64
+	err := w.Walk(ctx, func(_ context.Context, batch []int64) error {
+		if batch[0] == 5 {
+			return boom
+		}
+		seen = append(seen, batch...)
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("want boom, got %v", err)
+	}
+	if stored, _ := store.Load(ctx, "backfill"); stored != keyset.EncodeInt64Cursor(4) {
+		t.Fatalf("checkpoint must point at the last processed item, got %q", stored)
+	}
+
+	// Restart: the failed page is handed out again, nothing before it.
+	err = w.Walk(ctx, func(_ context.Context, batch []int64) error {
+		seen = append(seen, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(seen, ids) {
+		t.Fatalf("want %v, got %v", ids, seen)
+	}
+	if stored, _ := store.Load(ctx, "backfill"); stored != keyset.EncodeInt64Cursor(7) {
+		t.Fatalf("checkpoint must stay at the end of the scan, got %q", stored)
+	}
+}
+
+func TestCheckpointWalker_ResumesFromFirstAndLast(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ids := []int64{1, 2, 3, 4, 5, 6}
+	cases := []struct {
+		dir  keyset.Dir
+		want []int64
+	}{
+		{keyset.DirFirst, []int64{5, 6}},
+		{keyset.DirLast, []int64{1, 2, 3}},
+	}
+	for _, c := range cases {
+		store := keyset.NewMemoryCheckpointStore()
+		if err := store.Save(ctx, "scan", keyset.EncodeInt64Cursor(4)); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		calls := 0
+		w := keyset.CheckpointWalker[int64]{
+			Store:  store,
+			Key:    "scan",
+			Fetch:  memFetch(ids, &calls),
+			Cursor: keyset.EncodeInt64Cursor,
+			Page:   keyset.Page{Dir: c.dir, Limit: 2},
+		}
+		var seen []int64
+		if err := w.Walk(ctx, func(_ context.Context, batch []int64) error {
+			seen = append(seen, batch...)
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		slices.Sort(seen)
+		if !slices.Equal(seen, c.want) {
+			t.Fatalf("dir %d: must resume after the checkpoint, want %v, got %v", c.dir, c.want, seen)
+		}
+	}
+}
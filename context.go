@@ -0,0 +1,17 @@
+package keyset
+
+import "context"
+
+// pageKey is the context key for the Page stored by NewContext.
+type pageKey struct{}
+
+// NewContext returns a copy of ctx carrying p, e.g. from request middleware.
+func NewContext(ctx context.Context, p Page) context.Context {
+	return context.WithValue(ctx, pageKey{}, p)
+}
+
+// PageFromContext returns the Page stored in ctx by NewContext, if any.
+func PageFromContext(ctx context.Context) (Page, bool) {
+	p, ok := ctx.Value(pageKey{}).(Page)
+	return p, ok
+}
@@ -0,0 +1,21 @@
+package keyset_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestPageFromContext(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := keyset.PageFromContext(context.Background()); ok {
+		t.Fatal("empty context must not carry a page")
+	}
+	want := keyset.Page{Cursor: keyset.EncodeInt64Cursor(3), Dir: keyset.DirPrev, Limit: 10}
+	got, ok := keyset.PageFromContext(keyset.NewContext(context.Background(), want))
+	if !ok || got != want {
+		t.Fatalf("want %+v, got %+v (ok=%v)", want, got, ok)
+	}
+}
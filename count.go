@@ -0,0 +1,91 @@
+package keyset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DefaultCountLimit is the cap used by CountCapped when CountOptions.Limit is unset,
+// enough to render "10,000+".
+const DefaultCountLimit = 10001
+
+// CountMode selects how the total number of rows matching a base query
+// (without keyset window or LIMIT) is computed.
+type CountMode int
+
+const (
+	CountExact    CountMode = iota + 1 // COUNT(*) over the base query
+	CountCapped                        // COUNT(*) over at most CountOptions.Limit rows
+	CountEstimate                      // Planner estimate from PostgreSQL EXPLAIN (FORMAT JSON)
+)
+
+// CountOptions configures a count.
+type CountOptions struct {
+	Mode  CountMode
+	Limit int64 // Cap for CountCapped; defaults to DefaultCountLimit
+}
+
+// EnsureDefaults fills unset fields with default values.
+func (o *CountOptions) EnsureDefaults() {
+	if o.Mode == 0 {
+		o.Mode = CountExact
+	}
+	if o.Mode == CountCapped && o.Limit <= 0 {
+		o.Limit = DefaultCountLimit
+	}
+}
+
+// Total is a row count attached to a Result.
+type Total struct {
+	Count  int64
+	Mode   CountMode
+	Capped bool // CountCapped hit the cap: at least Count rows exist
+}
+
+// NewTotal returns the Total for a count of n obtained with opts.
+func NewTotal(n int64, opts CountOptions) Total {
+	opts.EnsureDefaults()
+	return Total{Count: n, Mode: opts.Mode, Capped: opts.Mode == CountCapped && n >= opts.Limit}
+}
+
+// Exact reports whether Count is the exact number of rows.
+func (t Total) Exact() bool {
+	return t.Mode == CountExact || (t.Mode == CountCapped && !t.Capped)
+}
+
+// CountFunc computes the total for a base query, see the adapters' Counter.
+type CountFunc func(ctx context.Context) (Total, error)
+
+// PaginateWithTotal is like Paginate and additionally attaches the total
+// computed by count to the result.
+func PaginateWithTotal[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T], count CountFunc) (Result[T], error) {
+	r, err := Paginate(ctx, p, fetch, cursor)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	total, err := count(ctx)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	r.Total = &total
+	return r, nil
+}
+
+// ParsePlanRows extracts the planner's row estimate for the top plan node
+// from the output of PostgreSQL's EXPLAIN (FORMAT JSON).
+func ParsePlanRows(plan []byte) (int64, error) {
+	var out []struct {
+		Plan struct {
+			Rows *float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &out); err != nil {
+		return 0, fmt.Errorf("keyset: parse plan: %w", err)
+	}
+	if len(out) == 0 || out[0].Plan.Rows == nil {
+		return 0, errors.New("keyset: parse plan: missing \"Plan Rows\"")
+	}
+	return int64(*out[0].Plan.Rows), nil
+}
@@ -0,0 +1,54 @@
+package keyset_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestNewTotal(t *testing.T) {
+	t.Parallel()
+
+	if tot := keyset.NewTotal(42, keyset.CountOptions{}); tot.Mode != keyset.CountExact || !tot.Exact() {
+		t.Fatalf("default mode must be exact: %+v", tot)
+	}
+	if tot := keyset.NewTotal(10001, keyset.CountOptions{Mode: keyset.CountCapped}); !tot.Capped || tot.Exact() {
+		t.Fatalf("count at the default cap must be capped: %+v", tot)
+	}
+	if tot := keyset.NewTotal(99, keyset.CountOptions{Mode: keyset.CountCapped, Limit: 100}); tot.Capped || !tot.Exact() {
+		t.Fatalf("count below the cap is exact: %+v", tot)
+	}
+	if tot := keyset.NewTotal(12400, keyset.CountOptions{Mode: keyset.CountEstimate}); tot.Exact() {
+		t.Fatalf("estimates are not exact: %+v", tot)
+	}
+}
+
+func TestParsePlanRows(t *testing.T) {
+	t.Parallel()
+
+	plan := `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "posts", "Plan Rows": 12400, "Plan Width": 40}}]`
+	n, err := keyset.ParsePlanRows([]byte(plan))
+	if err != nil || n != 12400 {
+		t.Fatalf("want 12400, got (%d, %v)", n, err)
+	}
+	if _, err := keyset.ParsePlanRows([]byte(`[{"Plan": {}}]`)); err == nil {
+		t.Fatalf("want error for a plan without rows")
+	}
+}
+
+func TestPaginateWithTotal(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	count := func(context.Context) (keyset.Total, error) {
+		return keyset.NewTotal(5, keyset.CountOptions{}), nil
+	}
+	res, err := keyset.PaginateWithTotal(context.Background(), keyset.Page{Limit: 2}, memFetch([]int64{1, 2, 3, 4, 5}, &calls), keyset.EncodeInt64Cursor, count)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Total == nil || res.Total.Count != 5 || len(res.Items) != 2 || !res.HasNext {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
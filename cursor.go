@@ -1,6 +1,7 @@
 package keyset
 
 import (
+	"cmp"
 	"encoding/base64"
 	"encoding/binary"
 	"errors"
@@ -83,8 +84,89 @@ func DecodeTimeAndInt64Cursor(s string) (time.Time, int64, error) {
 	return time.Unix(0, int64(tu)).UTC(), int64(idu), nil
 }
 
+// prefixMarker ends a partial cursor, so it cannot be mistaken for a plain
+// single-column cursor of the same leading bytes.
+const prefixMarker = 'P'
+
+// EncodeTimePrefixCursor encodes a partial composite cursor that sets only the
+// leading time column of a (time, id) key. It lets callers seek to an arbitrary
+// time ("jump to March 2024") without a row to derive a cursor from; builders
+// treat it as a bound on the time column alone, leaving id open-ended.
+// It is 8 bytes of time followed by a marker byte, so it differs from EncodeTimeCursor.
+func EncodeTimePrefixCursor(t time.Time) string {
+	var buf [9]byte
+	binary.BigEndian.PutUint64(buf[0:8], uint64(t.UTC().UnixNano()))
+	buf[8] = prefixMarker
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
+
+// DecodeTimeAndInt64PrefixCursor decodes either a full composite cursor
+// (EncodeTimeAndInt64Cursor) or a partial one (EncodeTimePrefixCursor).
+// full reports whether the id part is present; id is zero otherwise.
+// Plain time cursors (EncodeTimeCursor) are rejected with ErrCursorLength.
+func DecodeTimeAndInt64PrefixCursor(s string) (t time.Time, id int64, full bool, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, 0, false, fmt.Errorf("keyset: decode cursor: %w", err)
+	}
+	switch len(b) {
+	case 9:
+		if b[8] != prefixMarker {
+			return time.Time{}, 0, false, ErrCursorLength
+		}
+		return time.Unix(0, int64(binary.BigEndian.Uint64(b[0:8]))).UTC(), 0, false, nil
+	case 16:
+		tu := binary.BigEndian.Uint64(b[0:8])
+		idu := binary.BigEndian.Uint64(b[8:16])
+		return time.Unix(0, int64(tu)).UTC(), int64(idu), true, nil
+	default:
+		return time.Time{}, 0, false, ErrCursorLength
+	}
+}
+
 // EncodeNextCursor returns the next-page cursor derived from
 // the last visible record (display boundary).
 func EncodeNextCursor(t time.Time, id int64) string {
 	return EncodeTimeAndInt64Cursor(t, id)
 }
+
+// CompareCursors compares two cursors of the same encoding by the key values
+// they hold and returns -1, 0 or +1. Keys are compared column by column as
+// signed 64-bit integers; a partial cursor sorts before the full cursors
+// that share its leading columns.
+func CompareCursors(a, b string) (int, error) {
+	ab, err := base64.RawURLEncoding.DecodeString(a)
+	if err != nil {
+		return 0, fmt.Errorf("keyset: decode cursor: %w", err)
+	}
+	bb, err := base64.RawURLEncoding.DecodeString(b)
+	if err != nil {
+		return 0, fmt.Errorf("keyset: decode cursor: %w", err)
+	}
+	if ab, err = trimPrefixMarker(ab); err != nil {
+		return 0, err
+	}
+	if bb, err = trimPrefixMarker(bb); err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(ab) && i < len(bb); i += 8 {
+		av := int64(binary.BigEndian.Uint64(ab[i : i+8]))
+		bv := int64(binary.BigEndian.Uint64(bb[i : i+8]))
+		if c := cmp.Compare(av, bv); c != 0 {
+			return c, nil
+		}
+	}
+	return cmp.Compare(len(ab), len(bb)), nil
+}
+
+// trimPrefixMarker strips the marker of a partial cursor, leaving whole
+// 8-byte columns.
+func trimPrefixMarker(b []byte) ([]byte, error) {
+	if len(b)%8 == 1 && b[len(b)-1] == prefixMarker {
+		b = b[:len(b)-1]
+	}
+	if len(b)%8 != 0 {
+		return nil, ErrCursorLength
+	}
+	return b, nil
+}
@@ -122,3 +122,33 @@ func TestDecodeTimeAndInt64_InvalidLength(t *testing.T) {
 		t.Fatalf("expected ErrCursorLength, got %v", err)
 	}
 }
+
+func TestDecodeTimeAndInt64Prefix_FullAndPartial(t *testing.T) {
+	t.Parallel()
+	ts := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	gt, gid, full, err := keyset.DecodeTimeAndInt64PrefixCursor(keyset.EncodeTimeAndInt64Cursor(ts, 7))
+	if err != nil || !full || !gt.Equal(ts) || gid != 7 {
+		t.Fatalf("full cursor mismatch: (%v,%d,%v,%v)", gt, gid, full, err)
+	}
+
+	gt, gid, full, err = keyset.DecodeTimeAndInt64PrefixCursor(keyset.EncodeTimePrefixCursor(ts))
+	if err != nil || full || !gt.Equal(ts) || gid != 0 {
+		t.Fatalf("partial cursor mismatch: (%v,%d,%v,%v)", gt, gid, full, err)
+	}
+
+	// Prefix cursors are distinct from plain time cursors, which composite
+	// builders must not take for a prefix.
+	if keyset.EncodeTimePrefixCursor(ts) == keyset.EncodeTimeCursor(ts) {
+		t.Fatal("prefix cursor must differ from a time cursor")
+	}
+	for _, bad := range []string{
+		base64.RawURLEncoding.EncodeToString(make([]byte, 12)),
+		base64.RawURLEncoding.EncodeToString(make([]byte, 9)),
+		keyset.EncodeTimeCursor(ts),
+	} {
+		if _, _, _, err := keyset.DecodeTimeAndInt64PrefixCursor(bad); !errors.Is(err, keyset.ErrCursorLength) {
+			t.Fatalf("%q: expected ErrCursorLength, got %v", bad, err)
+		}
+	}
+}
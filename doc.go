@@ -21,8 +21,14 @@
 //
 // Core features:
 //   - Stable keyset pagination with bidirectional navigation
+//   - First/last page jumps with HasPrev/HasNext flags (Paginate)
+//   - Optional exact, capped or estimated totals (PaginateWithTotal)
 //   - Opaque cursor encoding (int64, time, or composite time+id)
+//   - Self-describing page tokens carrying direction, limit and snapshot (Token)
 //   - Direction- and order-aware SQL helpers
+//   - Iterators over all pages (All, Pages) for batch jobs
+//   - Change tailing with a commit-lag safety window (Tailer)
+//   - Request-scoped pages for middleware (NewContext, PageFromContext)
 //
 // For a practical example, see examples/kgorm.
 package keyset
@@ -32,56 +32,35 @@ func main() {
 		Dir:   keyset.DirNext, // move forward
 	}
 
-	// Walk two "next" pages
-	var cursor string
-	for i := 0; i < 2; i++ {
-		page.Cursor = cursor
-		posts, next := fetchPageByTimeAndID(ctx, db, page, keyset.Descending)
-		printPage(i+1, page.Dir, posts)
-		cursor = next // carry forward
-	}
-
-	// Now go "prev" once from the current cursor
-	page.Dir = keyset.DirPrev
-	page.Cursor = cursor
-	posts, prev := fetchPageByTimeAndID(ctx, db, page, keyset.Descending)
-	printPage(1, page.Dir, posts)
-	_ = prev // not used further; just showing the symmetric flow
+	// Walk two "next" pages. The tokens carry their direction and limit,
+	// so the follow-up request only needs the token (like ?cursor=... in an API).
+	res := fetchPageByTimeAndID(ctx, db, page, keyset.Descending)
+	printPage(1, keyset.DirNext, res.Items)
+	res = fetchPageByTimeAndID(ctx, db, keyset.Page{Cursor: res.NextToken(page.Limit)}, keyset.Descending)
+	printPage(2, keyset.DirNext, res.Items)
+
+	// Now go "prev" once: the prev token starts from the FIRST row of the page.
+	res = fetchPageByTimeAndID(ctx, db, keyset.Page{Cursor: res.PrevToken(page.Limit)}, keyset.Descending)
+	printPage(1, keyset.DirPrev, res.Items)
 }
 
-// fetchPageByTimeAndID runs a single page query by (created_at, id)
-// using DESC order, applies keyset windows, and returns results plus the
-// cursor for the next request in the SAME direction.
-// For DirNext: next cursor = last row boundary
-// For DirPrev: next cursor = last row boundary within the reversed (normalized) result
-func fetchPageByTimeAndID(ctx context.Context, db *gorm.DB, page keyset.Page, ord keyset.Order) ([]model.Post, string) {
-	var out []model.Post
-
+// fetchPageByTimeAndID runs a single page query by (created_at, id), applies
+// keyset windows, and returns the rows in display order together with the
+// cursors for both directions (see Result.NextToken / Result.PrevToken).
+func fetchPageByTimeAndID(ctx context.Context, db *gorm.DB, page keyset.Page, ord keyset.Order) keyset.Result[model.Post] {
 	// Build the query scope (WHERE + ORDER + LIMIT)
-	scope := kgorm.PageByTimeAndID(
-		db.WithContext(ctx).Model(&model.Post{}),
-		page,
-		ord,
-		"created_at",
-		"id",
-	)
-
-	// Execute + normalize order (DirPrev → reverse slice)
-	if tx := kgorm.FindPage(scope, page, &out); tx.Error != nil {
-		log.Fatalf("find page: %v", tx.Error)
+	scope := func(db *gorm.DB, p keyset.Page) *gorm.DB {
+		return kgorm.PageByTimeAndID(db, p, ord, "created_at", "id")
 	}
 
-	// If there are no rows, no cursor to return
-	if len(out) == 0 {
-		return out, ""
+	// Execute + normalize order (DirPrev → reverse slice) + HasPrev/HasNext
+	res, err := kgorm.Paginate(db.WithContext(ctx).Model(&model.Post{}), page, scope, func(p model.Post) string {
+		return keyset.EncodeTimeAndInt64Cursor(p.CreatedAt, p.ID)
+	})
+	if err != nil {
+		log.Fatalf("find page: %v", err)
 	}
-
-	// Compute the next cursor from the boundary row in the SAME direction.
-	// Because FindPage() normalized order (i.e., output is always display order),
-	// the boundary for the "next call" is the last row in 'out'.
-	last := out[len(out)-1]
-	nextCursor := keyset.EncodeTimeAndInt64Cursor(last.CreatedAt, last.ID)
-	return out, nextCursor
+	return res
 }
 
 func printPage(i int, dir keyset.Dir, posts []model.Post) {
@@ -0,0 +1,78 @@
+package keyset
+
+import (
+	"context"
+	"iter"
+)
+
+// FetchFunc loads a single page for p and returns its items in display order
+// (i.e. already normalized with NormalizePageResult).
+type FetchFunc[T any] func(ctx context.Context, p Page) ([]T, error)
+
+// CursorFunc encodes the keyset cursor of a single item,
+// e.g. func(p Post) string { return EncodeTimeAndInt64Cursor(p.CreatedAt, p.ID) }.
+type CursorFunc[T any] func(item T) string
+
+// Pages returns an iterator over successive pages starting at p.
+//
+// After each page the cursor is advanced to the boundary item in the direction
+// of travel (the last item for DirNext, the first item for DirPrev), and the
+// iteration stops when a page comes back shorter than p.Limit. DirFirst and
+// DirLast start at either end and continue with DirNext and DirPrev.
+// Fetch and context errors are yielded once, after which the iteration stops.
+func Pages[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T]) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		p.EnsureDefaults()
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			items, err := fetch(ctx, p)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(items) == 0 {
+				return
+			}
+			// Take the boundary before yielding: the caller may modify the batch.
+			boundary := BoundaryItem(p, items)
+			if !yield(items, nil) || len(items) < p.Limit {
+				return
+			}
+			// Follow-up pages start after the boundary item, whatever p.Bound was.
+			p.Cursor, p.Bound = cursor(boundary), Exclusive
+			p.Dir = p.Dir.onward()
+		}
+	}
+}
+
+// All returns an iterator over every item reachable from p, fetching pages
+// lazily as the caller advances. See Pages for the paging and error semantics.
+func All[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for items, err := range Pages(ctx, p, fetch, cursor) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// BoundaryItem returns the item a follow-up page in p.Dir continues from:
+// the last item for DirNext/DirFirst and the first one for DirPrev/DirLast.
+// items must be in display order and non-empty.
+func BoundaryItem[T any](p Page, items []T) T {
+	if p.Dir.Backward() {
+		return items[0]
+	}
+	return items[len(items)-1]
+}
@@ -0,0 +1,178 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// memFetch serves pages from ids (sorted ascending) using int64 cursors,
+// mimicking what an adapter does for a single-column ASC key.
+func memFetch(ids []int64, calls *int) keyset.FetchFunc[int64] {
+	return func(_ context.Context, p keyset.Page) ([]int64, error) {
+		*calls++
+		c, err := keyset.DecodeInt64Cursor(p.Cursor)
+		hasCursor := err == nil
+		snap, err := keyset.DecodeInt64Cursor(p.Snapshot)
+		hasSnapshot := err == nil
+		// skip reports whether id lies on the wrong side of the cursor or snapshot.
+		skip := func(id int64) bool {
+			if hasSnapshot && id > snap {
+				return true
+			}
+			if !hasCursor || (p.Bound == keyset.Inclusive && id == c) {
+				return false
+			}
+			if p.Dir.Backward() {
+				return id >= c
+			}
+			return id <= c
+		}
+
+		var window []int64
+		if p.Dir.Backward() {
+			for i := len(ids) - 1; i >= 0 && len(window) < p.Limit; i-- {
+				if !skip(ids[i]) {
+					window = append(window, ids[i])
+				}
+			}
+		} else {
+			for _, id := range ids {
+				if len(window) == p.Limit {
+					break
+				}
+				if !skip(id) {
+					window = append(window, id)
+				}
+			}
+		}
+		return keyset.NormalizePageResult(p, window), nil
+	}
+}
+
+func TestPages_WalksUntilShortPage(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7}
+	calls := 0
+	var got [][]int64
+	for batch, err := range keyset.Pages(context.Background(), keyset.Page{Limit: 3}, memFetch(ids, &calls), keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, batch)
+	}
+
+	want := [][]int64{{1, 2, 3}, {4, 5, 6}, {7}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]int64]) {
+		t.Fatalf("pages mismatch: want %v, got %v", want, got)
+	}
+	if calls != 3 {
+		t.Fatalf("fetch calls want 3, got %d", calls)
+	}
+}
+
+func TestAll_DirPrevFollowsFirstItem(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5}
+	calls := 0
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(5), Limit: 2, Dir: keyset.DirPrev}
+	var got []int64
+	for id, err := range keyset.All(context.Background(), p, memFetch(ids, &calls), keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, id)
+	}
+
+	// Each page is in display order; pages themselves move backward.
+	want := []int64{3, 4, 1, 2}
+	if !slices.Equal(got, want) {
+		t.Fatalf("items mismatch: want %v, got %v", want, got)
+	}
+	if calls != 3 {
+		t.Fatalf("fetch calls want 3 (last one empty), got %d", calls)
+	}
+}
+
+func TestAll_StopsEarlyOnBreak(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	for id, err := range keyset.All(context.Background(), keyset.Page{Limit: 2}, memFetch([]int64{1, 2, 3, 4}, &calls), keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id == 1 {
+			break
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("fetch calls want 1, got %d", calls)
+	}
+}
+
+func TestAll_SurfacesErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fetch error", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		fetch := func(context.Context, keyset.Page) ([]int64, error) { return nil, boom }
+
+		n := 0
+		for _, err := range keyset.All(context.Background(), keyset.Page{}, fetch, keyset.EncodeInt64Cursor) {
+			n++
+			if !errors.Is(err, boom) {
+				t.Fatalf("want boom, got %v", err)
+			}
+		}
+		if n != 1 {
+			t.Fatalf("want exactly one yield, got %d", n)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := 0
+		var gotErr error
+		for id, err := range keyset.All(ctx, keyset.Page{Limit: 1}, memFetch([]int64{1, 2, 3}, &calls), keyset.EncodeInt64Cursor) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			if id == 1 {
+				cancel()
+			}
+		}
+		if !errors.Is(gotErr, context.Canceled) {
+			t.Fatalf("want context.Canceled, got %v", gotErr)
+		}
+		if calls != 1 {
+			t.Fatalf("fetch calls want 1, got %d", calls)
+		}
+	})
+}
+
+func TestPages_InclusiveOnlyForFirstPage(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(2), Bound: keyset.Inclusive, Limit: 2}
+	var got []int64
+	for id, err := range keyset.All(context.Background(), p, memFetch([]int64{1, 2, 3, 4, 5}, &calls), keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, id)
+	}
+	if want := []int64{2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/mickamy/go-keyset/kchi
+
+go 1.23.0
+
+replace (
+	github.com/mickamy/go-keyset => ..
+	github.com/mickamy/go-keyset/khttp => ../khttp
+)
+
+require (
+	github.com/go-chi/chi/v5 v5.2.5
+	github.com/mickamy/go-keyset v0.0.0
+	github.com/mickamy/go-keyset/khttp v0.0.0
+)
@@ -0,0 +1,33 @@
+// Package kchi adapts khttp to the chi router.
+//
+// chi uses standard net/http middleware, so Middleware is khttp.Middleware
+// under a name that reads well in chi routes:
+//
+//	r := chi.NewRouter()
+//	r.With(kchi.Middleware(binder)).Get("/posts", listPosts)
+//
+//	func listPosts(w http.ResponseWriter, r *http.Request) {
+//		page := kchi.Page(r)
+//		// ...
+//	}
+package kchi
+
+import (
+	"net/http"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+// Middleware binds the page of every request with b and stores it in the
+// request context; invalid parameters are answered with a 400.
+func Middleware(b khttp.Binder) func(http.Handler) http.Handler {
+	return khttp.Middleware(b)
+}
+
+// Page returns the page stored by Middleware, or the zero Page when the
+// route is not wrapped by it.
+func Page(r *http.Request) keyset.Page {
+	p, _ := keyset.PageFromContext(r.Context())
+	return p
+}
@@ -0,0 +1,38 @@
+package kchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kchi"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got keyset.Page
+	r := chi.NewRouter()
+	r.With(kchi.Middleware(khttp.Binder{})).Get("/posts", func(w http.ResponseWriter, r *http.Request) {
+		got = kchi.Page(r)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?dir=last&limit=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d", rec.Code)
+	}
+	if want := (keyset.Page{Dir: keyset.DirLast, Limit: 5}); got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?dir=prev", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,26 @@
+module github.com/mickamy/go-keyset/kecho
+
+go 1.24.0
+
+replace (
+	github.com/mickamy/go-keyset => ..
+	github.com/mickamy/go-keyset/khttp => ../khttp
+)
+
+require (
+	github.com/labstack/echo/v4 v4.15.1
+	github.com/mickamy/go-keyset v0.0.0
+	github.com/mickamy/go-keyset/khttp v0.0.0
+)
+
+require (
+	github.com/labstack/gommon v0.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.46.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+)
@@ -0,0 +1,43 @@
+// Package kecho adapts khttp to the Echo framework.
+//
+//	e := echo.New()
+//	e.GET("/posts", listPosts, kecho.Middleware(binder))
+//
+//	func listPosts(c echo.Context) error {
+//		page := kecho.Page(c)
+//		// ...
+//	}
+package kecho
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+// Middleware binds the page of every request with b and stores it in the
+// request context. Invalid parameters are answered with the 400 of
+// khttp.WriteError, bypassing Echo's error handler so every router returns
+// the same body.
+func Middleware(b khttp.Binder) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+			p, err := b.Bind(r)
+			if err != nil {
+				khttp.WriteError(c.Response(), err)
+				return nil
+			}
+			c.SetRequest(r.WithContext(keyset.NewContext(r.Context(), p)))
+			return next(c)
+		}
+	}
+}
+
+// Page returns the page stored by Middleware, or the zero Page when the
+// route is not wrapped by it.
+func Page(c echo.Context) keyset.Page {
+	p, _ := keyset.PageFromContext(c.Request().Context())
+	return p
+}
@@ -0,0 +1,40 @@
+package kecho_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kecho"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got keyset.Page
+	e := echo.New()
+	e.GET("/posts", func(c echo.Context) error {
+		got = kecho.Page(c)
+		return c.NoContent(http.StatusOK)
+	}, kecho.Middleware(khttp.Binder{}))
+
+	cur := keyset.EncodeInt64Cursor(9)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?after="+cur+"&limit=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d", rec.Code)
+	}
+	if want := (keyset.Page{Cursor: cur, Dir: keyset.DirNext, Limit: 5}); got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?limit=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", rec.Code)
+	}
+}
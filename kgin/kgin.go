@@ -0,0 +1,40 @@
+// Package kgin adapts khttp to the Gin framework.
+//
+//	r := gin.New()
+//	r.GET("/posts", kgin.Middleware(binder), listPosts)
+//
+//	func listPosts(c *gin.Context) {
+//		page := kgin.Page(c)
+//		// ...
+//	}
+package kgin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+// Middleware binds the page of every request with b and stores it in the
+// request context. Invalid parameters are answered with the 400 of
+// khttp.WriteError and abort the handler chain.
+func Middleware(b khttp.Binder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := b.Bind(c.Request)
+		if err != nil {
+			khttp.WriteError(c.Writer, err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(keyset.NewContext(c.Request.Context(), p))
+		c.Next()
+	}
+}
+
+// Page returns the page stored by Middleware, or the zero Page when the
+// route is not wrapped by it.
+func Page(c *gin.Context) keyset.Page {
+	p, _ := keyset.PageFromContext(c.Request.Context())
+	return p
+}
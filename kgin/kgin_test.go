@@ -0,0 +1,43 @@
+package kgin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgin"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	var got keyset.Page
+	called := false
+	r := gin.New()
+	r.GET("/posts", kgin.Middleware(khttp.Binder{Limit: khttp.LimitPolicy{Default: 15}}), func(c *gin.Context) {
+		called = true
+		got = kgin.Page(c)
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?dir=first", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d", rec.Code)
+	}
+	if want := (keyset.Page{Dir: keyset.DirFirst, Limit: 15}); got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	called = false
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?dir=up", nil))
+	if rec.Code != http.StatusBadRequest || called {
+		t.Fatalf("want 400 without calling the handler, got %d (called=%v)", rec.Code, called)
+	}
+}
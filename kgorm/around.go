@@ -0,0 +1,19 @@
+package kgorm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Around runs the two windows of an "around" page anchored at p.Cursor (see
+// keyset.AroundPages) and merges them in display order. The context is taken
+// from db (see gorm.DB.WithContext).
+//
+//	scope := func(db *gorm.DB, p keyset.Page) *gorm.DB {
+//		return kgorm.PageByTimeAndID(db, p, keyset.Ascending, "created_at", "id")
+//	}
+//	res, err := kgorm.Around(db.Model(&Message{}), keyset.Page{Cursor: anchor, Limit: 25}, scope, messageCursor)
+func Around[T any](db *gorm.DB, p keyset.Page, scope Scope, cursor keyset.CursorFunc[T]) (keyset.Result[T], error) {
+	return keyset.Around(statementContext(db), p, Fetch[T](db, scope), cursor)
+}
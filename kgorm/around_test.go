@@ -0,0 +1,46 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func TestAround(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	// before: DirPrev window (ASC base → DESC), one probe row beyond the limit
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" WHERE id < $1 ORDER BY id DESC LIMIT $2`)).
+		WithArgs(int64(10), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9).AddRow(8).AddRow(7))
+	// after: inclusive DirNext window, anchor plus probe row
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" WHERE id >= $1 ORDER BY id ASC LIMIT $2`)).
+		WithArgs(int64(10), 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(10), Limit: 2}
+	res, err := kgorm.Around(db.Model(&Post{}), p, scopeByID, postCursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int64
+	for _, post := range res.Items {
+		ids = append(ids, post.ID)
+	}
+	if !slices.Equal(ids, []int64{8, 9, 10, 11}) {
+		t.Fatalf("items mismatch: %v", ids)
+	}
+	if !res.HasPrev || res.HasNext {
+		t.Fatalf("want HasPrev only, got %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
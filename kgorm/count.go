@@ -0,0 +1,62 @@
+package kgorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Count counts the rows matched by db, the base query (model and filters,
+// without keyset scope), according to opts. It runs on cloned sessions, so db
+// can still be used for the page query afterwards:
+//
+//	CountExact:    SELECT count(*) FROM "posts" WHERE ...
+//	CountCapped:   SELECT count(*) FROM (SELECT 1 FROM "posts" WHERE ... LIMIT n) AS keyset_count
+//	CountEstimate: EXPLAIN (FORMAT JSON) SELECT * FROM "posts" WHERE ...   -- PostgreSQL only
+func Count(db *gorm.DB, opts keyset.CountOptions) (keyset.Total, error) {
+	opts.EnsureDefaults()
+	var n int64
+	switch opts.Mode {
+	case keyset.CountCapped:
+		sub := db.Session(&gorm.Session{}).Select("1").Limit(int(opts.Limit))
+		if err := db.Session(&gorm.Session{NewDB: true}).Table("(?) AS keyset_count", sub).Count(&n).Error; err != nil {
+			return keyset.Total{}, err
+		}
+	case keyset.CountEstimate:
+		stmt := db.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{}).Statement
+		var plan []byte
+		row := db.Session(&gorm.Session{NewDB: true}).Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Row()
+		if err := row.Scan(&plan); err != nil {
+			return keyset.Total{}, err
+		}
+		rows, err := keyset.ParsePlanRows(plan)
+		if err != nil {
+			return keyset.Total{}, err
+		}
+		n = rows
+	default:
+		if err := db.Session(&gorm.Session{}).Count(&n).Error; err != nil {
+			return keyset.Total{}, err
+		}
+	}
+	return keyset.NewTotal(n, opts), nil
+}
+
+// Counter returns a keyset.CountFunc that runs Count for db with the
+// context passed to it.
+func Counter(db *gorm.DB, opts keyset.CountOptions) keyset.CountFunc {
+	return func(ctx context.Context) (keyset.Total, error) {
+		return Count(db.WithContext(ctx), opts)
+	}
+}
+
+// PaginateWithTotal is like Paginate and also attaches the total of db to the
+// result (see keyset.PaginateWithTotal):
+//
+//	res, err := kgorm.PaginateWithTotal(db.Model(&Post{}), page, scope, postCursor,
+//		keyset.CountOptions{Mode: keyset.CountCapped})
+func PaginateWithTotal[T any](db *gorm.DB, p keyset.Page, scope Scope, cursor keyset.CursorFunc[T], opts keyset.CountOptions) (keyset.Result[T], error) {
+	return keyset.PaginateWithTotal(statementContext(db), p, Fetch[T](db, scope), cursor, Counter(db, opts))
+}
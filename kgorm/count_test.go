@@ -0,0 +1,87 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		opts  keyset.CountOptions
+		query string
+		rows  *sqlmock.Rows
+		want  keyset.Total
+	}{
+		{
+			name:  "exact",
+			opts:  keyset.CountOptions{Mode: keyset.CountExact},
+			query: `SELECT count(*) FROM "posts" WHERE title = $1`,
+			rows:  sqlmock.NewRows([]string{"count"}).AddRow(42),
+			want:  keyset.Total{Count: 42, Mode: keyset.CountExact},
+		},
+		{
+			name:  "capped",
+			opts:  keyset.CountOptions{Mode: keyset.CountCapped, Limit: 10},
+			query: `SELECT count(*) FROM (SELECT 1 FROM "posts" WHERE title = $1 LIMIT $2) AS keyset_count`,
+			rows:  sqlmock.NewRows([]string{"count"}).AddRow(10),
+			want:  keyset.Total{Count: 10, Mode: keyset.CountCapped, Capped: true},
+		},
+		{
+			name:  "estimate",
+			opts:  keyset.CountOptions{Mode: keyset.CountEstimate},
+			query: `EXPLAIN (FORMAT JSON) SELECT * FROM "posts" WHERE title = $1`,
+			rows:  sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(`[{"Plan": {"Plan Rows": 12400}}]`),
+			want:  keyset.Total{Count: 12400, Mode: keyset.CountEstimate},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock := openMock(t)
+			mock.ExpectQuery(regexp.QuoteMeta(c.query)).WillReturnRows(c.rows)
+
+			base := db.Model(&Post{}).Where("title = ?", "hello")
+			got, err := kgorm.Count(base, c.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("want %+v, got %+v", c.want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPaginateWithTotal_ReusesBase(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" ORDER BY id ASC LIMIT $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "posts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	res, err := kgorm.PaginateWithTotal(db.Model(&Post{}), keyset.Page{Limit: 2}, scopeByID, postCursor, keyset.CountOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Total == nil || res.Total.Count != 2 || len(res.Items) != 2 || res.HasNext {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
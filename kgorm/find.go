@@ -7,7 +7,7 @@ import (
 )
 
 // FindPage executes the provided GORM query into out and normalizes the
-// result order for keyset pagination. If page.Dir is DirPrev, it reverses
+// result order for keyset pagination. If page.Dir is DirPrev or DirLast, it reverses
 // the slice in-place via keyset.NormalizePageResult so that the final order
 // matches the requested display order.
 //
@@ -0,0 +1,53 @@
+package kgorm
+
+import (
+	"context"
+	"iter"
+
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Scope applies a keyset window for p to db, typically a closure over
+// PageByID, PageByTime or PageByTimeAndID.
+type Scope func(db *gorm.DB, p keyset.Page) *gorm.DB
+
+// Fetch returns a keyset.FetchFunc that applies scope to a fresh session of db
+// for every page and returns the rows in display order.
+func Fetch[T any](db *gorm.DB, scope Scope) keyset.FetchFunc[T] {
+	return func(ctx context.Context, p keyset.Page) ([]T, error) {
+		var out []T
+		if tx := FindPage(scope(db.WithContext(ctx), p), p, &out); tx.Error != nil {
+			return nil, tx.Error
+		}
+		return out, nil
+	}
+}
+
+// All returns an iterator over every record reachable from p, driving the
+// cursor automatically. The context is taken from db (see gorm.DB.WithContext).
+//
+//	scope := func(db *gorm.DB, p keyset.Page) *gorm.DB {
+//		return kgorm.PageByID(db, p, keyset.Ascending, "id")
+//	}
+//	for post, err := range kgorm.All(db.Model(&Post{}), keyset.Page{Limit: 500}, scope, postCursor) {
+//		if err != nil { ... }
+//	}
+//
+// See keyset.Pages for the stop and error semantics.
+func All[T any](db *gorm.DB, p keyset.Page, scope Scope, cursor keyset.CursorFunc[T]) iter.Seq2[T, error] {
+	return keyset.All(statementContext(db), p, Fetch[T](db, scope), cursor)
+}
+
+// Pages is like All but yields one slice per page.
+func Pages[T any](db *gorm.DB, p keyset.Page, scope Scope, cursor keyset.CursorFunc[T]) iter.Seq2[[]T, error] {
+	return keyset.Pages(statementContext(db), p, Fetch[T](db, scope), cursor)
+}
+
+func statementContext(db *gorm.DB) context.Context {
+	if db.Statement != nil && db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+	return context.Background()
+}
@@ -0,0 +1,64 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func postCursor(p Post) string { return keyset.EncodeInt64Cursor(p.ID) }
+
+func scopeByID(db *gorm.DB, p keyset.Page) *gorm.DB {
+	return kgorm.PageByID(db, p, keyset.Ascending, "id")
+}
+
+func TestAll_DrivesCursor(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" ORDER BY id ASC LIMIT $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" WHERE id > $1 ORDER BY id ASC LIMIT $2`)).
+		WithArgs(int64(2), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	var got []int64
+	for post, err := range kgorm.All(db.Model(&Post{}), keyset.Page{Limit: 2}, scopeByID, postCursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, post.ID)
+	}
+
+	if !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Fatalf("rows mismatch: %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestPages_SurfacesQueryError(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "posts"`).WillReturnError(gorm.ErrInvalidDB)
+
+	n := 0
+	for batch, err := range kgorm.Pages(db.Model(&Post{}), keyset.Page{Limit: 2}, scopeByID, postCursor) {
+		n++
+		if batch != nil || err == nil {
+			t.Fatalf("want (nil, err), got (%v, %v)", batch, err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("want exactly one yield, got %d", n)
+	}
+}
@@ -39,6 +39,25 @@ func openDryRun(t *testing.T) *gorm.DB {
 	return db
 }
 
+// openMock returns a GORM *DB backed by sqlmock so tests can script the rows
+// returned for each query.
+func openMock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open mock db: %v", err)
+	}
+
+	return db, mock
+}
+
 // toSQL builds and "executes" (DryRun) the query and returns SQL and Vars.
 func toSQL[T any](q *gorm.DB) (string, []any) {
 	var out []T
@@ -0,0 +1,17 @@
+package kgorm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Paginate runs a single page query and returns it with boundary cursors and
+// HasPrev/HasNext flags (see keyset.Paginate). The context is taken from db
+// (see gorm.DB.WithContext). Use keyset.DirFirst and keyset.DirLast to jump
+// to either end of the list:
+//
+//	res, err := kgorm.Paginate(db.Model(&Post{}), keyset.Page{Dir: keyset.DirLast, Limit: 20}, scope, postCursor)
+func Paginate[T any](db *gorm.DB, p keyset.Page, scope Scope, cursor keyset.CursorFunc[T]) (keyset.Result[T], error) {
+	return keyset.Paginate(statementContext(db), p, Fetch[T](db, scope), cursor)
+}
@@ -0,0 +1,42 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func TestPaginate_Last(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	// DirLast ignores the cursor and reverses the ASC base order, with one probe row.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" ORDER BY id DESC LIMIT $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20).AddRow(19).AddRow(18))
+
+	p := keyset.Page{Dir: keyset.DirLast, Cursor: keyset.EncodeInt64Cursor(5), Limit: 2}
+	res, err := kgorm.Paginate(db.Model(&Post{}), p, scopeByID, postCursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int64
+	for _, post := range res.Items {
+		ids = append(ids, post.ID)
+	}
+	if !slices.Equal(ids, []int64{19, 20}) {
+		t.Fatalf("items mismatch: %v", ids)
+	}
+	if !res.HasPrev || res.HasNext {
+		t.Fatalf("want HasPrev only, got %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package kgorm
 
 import (
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -13,6 +14,9 @@ import (
 // Behavior:
 //   - Uses opaque cursors produced by keyset.EncodeInt64Cursor.
 //   - For DirPrev, it reverses ORDER BY to fetch the previous window.
+//   - p.Bound set to keyset.Inclusive also returns the cursor row itself.
+//   - A valid p.Until stops the window at a second cursor (see keyset.Page).
+//   - A valid p.Snapshot keeps only rows with col <= snapshot.
 //   - Use FindPage (or keyset.NormalizePageResult) to restore display order for DirPrev.
 func PageByID(db *gorm.DB, p keyset.Page, ord keyset.Order, col string) *gorm.DB {
 	p.EnsureDefaults()
@@ -25,7 +29,24 @@ func PageByID(db *gorm.DB, p keyset.Page, ord keyset.Order, col string) *gorm.DB
 			db.Logger.Warn(db.Statement.Context, "invalid pagination cursor: cursor=%v error=%v", p.Cursor, err)
 		} else {
 			// Apply the inequality operator based on effective order.
-			db = db.Where(fmt.Sprintf("%s %s ?", col, effective.InequalityOp()), id)
+			db = db.Where(fmt.Sprintf("%s %s ?", col, effective.BoundOp(p.Bound)), id)
+		}
+	}
+	if p.Until != "" {
+		id, err := keyset.DecodeInt64Cursor(p.Until)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination until cursor: cursor=%v error=%v", p.Until, err)
+		} else {
+			until := keyset.UntilOrder(ord, p.Dir)
+			db = db.Where(fmt.Sprintf("%s %s ?", col, until.BoundOp(p.UntilBound)), id)
+		}
+	}
+	if p.Snapshot != "" {
+		id, err := keyset.DecodeInt64Cursor(p.Snapshot)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination snapshot: cursor=%v error=%v", p.Snapshot, err)
+		} else {
+			db = db.Where(fmt.Sprintf("%s <= ?", col), id)
 		}
 	}
 
@@ -47,7 +68,24 @@ func PageByTime(db *gorm.DB, p keyset.Page, ord keyset.Order, col string) *gorm.
 			db.Logger.Warn(db.Statement.Context, "invalid pagination cursor: cursor=%v error=%v", p.Cursor, err)
 		} else {
 			// Apply the inequality operator based on effective order.
-			db = db.Where(fmt.Sprintf("%s %s ?", col, effective.InequalityOp()), tm)
+			db = db.Where(fmt.Sprintf("%s %s ?", col, effective.BoundOp(p.Bound)), tm)
+		}
+	}
+	if p.Until != "" {
+		tm, err := keyset.DecodeTimeCursor(p.Until)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination until cursor: cursor=%v error=%v", p.Until, err)
+		} else {
+			until := keyset.UntilOrder(ord, p.Dir)
+			db = db.Where(fmt.Sprintf("%s %s ?", col, until.BoundOp(p.UntilBound)), tm)
+		}
+	}
+	if p.Snapshot != "" {
+		tm, err := keyset.DecodeTimeCursor(p.Snapshot)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination snapshot: cursor=%v error=%v", p.Snapshot, err)
+		} else {
+			db = db.Where(fmt.Sprintf("%s <= ?", col), tm)
 		}
 	}
 
@@ -57,7 +95,8 @@ func PageByTime(db *gorm.DB, p keyset.Page, ord keyset.Order, col string) *gorm.
 
 // PageByTimeAndID applies keyset pagination over a composite key (time, id).
 // Columns must refer to the time and id columns respectively.
-// Cursor must be produced by keyset.EncodeTimeAndInt64Cursor.
+// Cursor must be produced by keyset.EncodeTimeAndInt64Cursor, or by
+// keyset.EncodeTimePrefixCursor to seek by time alone (time < :t for DESC).
 //
 // Stable window (DESC):
 //
@@ -67,19 +106,39 @@ func PageByTime(db *gorm.DB, p keyset.Page, ord keyset.Order, col string) *gorm.
 //
 //	(time > :t) OR (time = :t AND id > :id)
 //
+// With p.Bound set to keyset.Inclusive the id comparison becomes "<=" / ">=".
+// A valid p.Until adds the mirrored window on the other side (see keyset.UntilOrder).
+// A valid p.Snapshot keeps only rows at or below it (the inclusive DESC window).
+//
 // Note: Use FindPage (or keyset.NormalizePageResult) to restore display order for DirPrev.
 func PageByTimeAndID(db *gorm.DB, p keyset.Page, ord keyset.Order, timeCol, idCol string) *gorm.DB {
 	p.EnsureDefaults()
 	effective := keyset.EffectiveOrder(ord, p.Dir)
 
 	if p.Cursor != "" {
-		tm, id, err := keyset.DecodeTimeAndInt64Cursor(p.Cursor)
+		tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Cursor)
 		if err != nil {
 			db.Logger.Warn(db.Statement.Context, "invalid pagination cursor: cursor=%v error=%v", p.Cursor, err)
 		} else {
-			// Build the stable composite WHERE fragment and bind values (t, t, id).
-			where := keyset.StableWhereTimeAndID(timeCol, idCol, effective)
-			db = db.Where(where, tm, tm, id)
+			db = whereTimeAndID(db, timeCol, idCol, effective, p.Bound, tm, id, full)
+		}
+	}
+	if p.Until != "" {
+		tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Until)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination until cursor: cursor=%v error=%v", p.Until, err)
+		} else {
+			// Mirror the window on the other side of Until.
+			db = whereTimeAndID(db, timeCol, idCol, keyset.UntilOrder(ord, p.Dir), p.UntilBound, tm, id, full)
+		}
+	}
+	if p.Snapshot != "" {
+		tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Snapshot)
+		if err != nil {
+			db.Logger.Warn(db.Statement.Context, "invalid pagination snapshot: cursor=%v error=%v", p.Snapshot, err)
+		} else {
+			// Keys at or below the snapshot: the inclusive DESC window.
+			db = whereTimeAndID(db, timeCol, idCol, keyset.Descending, keyset.Inclusive, tm, id, full)
 		}
 	}
 
@@ -87,3 +146,13 @@ func PageByTimeAndID(db *gorm.DB, p keyset.Page, ord keyset.Order, timeCol, idCo
 	order := keyset.OrderClause([]string{timeCol, idCol}, effective)
 	return db.Order(order).Limit(p.Limit)
 }
+
+// whereTimeAndID applies the composite window for (tm, id), or a bound on the
+// time column alone for a partial key.
+func whereTimeAndID(db *gorm.DB, timeCol, idCol string, ord keyset.Order, bound keyset.Bound, tm time.Time, id int64, full bool) *gorm.DB {
+	if !full {
+		return db.Where(fmt.Sprintf("%s %s ?", timeCol, ord.BoundOp(bound)), tm)
+	}
+	// Build the stable composite WHERE fragment and bind values (t, t, id).
+	return db.Where(keyset.StableWhereTimeAndIDBound(timeCol, idCol, ord, bound), tm, tm, id)
+}
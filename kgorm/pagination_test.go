@@ -206,3 +206,95 @@ func TestPageByTime_Simple(t *testing.T) {
 		}
 	})
 }
+
+func TestPageByTimeAndID_BoundedByUntil(t *testing.T) {
+	t.Parallel()
+	db := openDryRun(t)
+
+	from := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	page := keyset.Page{
+		Cursor: keyset.EncodeTimeAndInt64Cursor(from, 9),
+		Until:  keyset.EncodeTimeAndInt64Cursor(to, 3),
+		Limit:  20,
+	}
+	sql, vars := toSQL[Post](kgorm.PageByTimeAndID(
+		db.Model(&Post{}), page, keyset.Descending, "created_at", "id",
+	))
+
+	if !strings.Contains(sql, "((created_at < $1) OR (created_at = $2 AND id < $3))") {
+		t.Fatalf("missing lower window, got: %s", sql)
+	}
+	if !strings.Contains(sql, "((created_at > $4) OR (created_at = $5 AND id > $6))") {
+		t.Fatalf("missing upper window, got: %s", sql)
+	}
+	if len(vars) != 7 || vars[6] != 20 {
+		t.Fatalf("vars mismatch: %v", vars)
+	}
+	if tm, ok := vars[3].(time.Time); !ok || !tm.Equal(to) {
+		t.Fatalf("vars[3] want until time=%v, got %v", to, vars[3])
+	}
+}
+
+func TestPageByID_InclusiveUntil(t *testing.T) {
+	t.Parallel()
+	db := openDryRun(t)
+
+	page := keyset.Page{Until: keyset.EncodeInt64Cursor(50), UntilBound: keyset.Inclusive, Limit: 5}
+	sql, vars := toSQL[Post](kgorm.PageByID(db.Model(&Post{}), page, keyset.Ascending, "id"))
+
+	if !strings.Contains(sql, "WHERE id <= $1") {
+		t.Fatalf("missing inclusive until window, got: %s", sql)
+	}
+	if len(vars) != 2 || vars[0] != int64(50) || vars[1] != 5 {
+		t.Fatalf("vars mismatch: %v", vars)
+	}
+}
+
+func TestPageByTimeAndID_InclusiveCursor(t *testing.T) {
+	t.Parallel()
+	db := openDryRun(t)
+
+	ts := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	page := keyset.Page{Cursor: keyset.EncodeTimeAndInt64Cursor(ts, 5), Bound: keyset.Inclusive, Limit: 3}
+	sql, _ := toSQL[Post](kgorm.PageByTimeAndID(
+		db.Model(&Post{}), page, keyset.Descending, "created_at", "id",
+	))
+
+	if !strings.Contains(sql, "(created_at < $1) OR (created_at = $2 AND id <= $3)") {
+		t.Fatalf("missing inclusive composite window, got: %s", sql)
+	}
+}
+
+func TestPageByTimeAndID_PartialKey(t *testing.T) {
+	t.Parallel()
+	db := openDryRun(t)
+
+	ts := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	page := keyset.Page{Cursor: keyset.EncodeTimePrefixCursor(ts), Bound: keyset.Inclusive, Limit: 3, Dir: keyset.DirPrev}
+	sql, vars := toSQL[Post](kgorm.PageByTimeAndID(
+		db.Model(&Post{}), page, keyset.Descending, "created_at", "id",
+	))
+
+	if !strings.Contains(sql, "WHERE created_at >= $1 ORDER BY created_at ASC, id ASC") {
+		t.Fatalf("missing leading-column window, got: %s", sql)
+	}
+	if len(vars) != 2 || vars[0] != ts {
+		t.Fatalf("vars mismatch: %v", vars)
+	}
+}
+
+func TestPageByID_Snapshot(t *testing.T) {
+	t.Parallel()
+	db := openDryRun(t)
+
+	page := keyset.Page{Dir: keyset.DirFirst, Snapshot: keyset.EncodeInt64Cursor(42), Limit: 3}
+	sql, vars := toSQL[Post](kgorm.PageByID(db.Model(&Post{}), page, keyset.Descending, "id"))
+
+	if !strings.Contains(sql, "WHERE id <= $1 ORDER BY id DESC LIMIT $2") {
+		t.Fatalf("missing snapshot bound, got: %s", sql)
+	}
+	if len(vars) != 2 || vars[0] != int64(42) {
+		t.Fatalf("vars mismatch: %v", vars)
+	}
+}
@@ -0,0 +1,13 @@
+package kgorm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Poll fetches up to limit records newer than head in base order (see
+// keyset.Poll). The context is taken from db.
+func Poll[T any](db *gorm.DB, head string, limit int, scope Scope, cursor keyset.CursorFunc[T]) (keyset.PollResult[T], error) {
+	return keyset.Poll(statementContext(db), head, limit, Fetch[T](db, scope), cursor)
+}
@@ -0,0 +1,38 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	// ASC base: items before the head come from the DirPrev window, plus a probe row.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts" WHERE id < $1 ORDER BY id DESC LIMIT $2`)).
+		WithArgs(int64(10), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9).AddRow(8))
+
+	res, err := kgorm.Poll(db.Model(&Post{}), keyset.EncodeInt64Cursor(10), 2, scopeByID, postCursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int64
+	for _, post := range res.Items {
+		ids = append(ids, post.ID)
+	}
+	if !slices.Equal(ids, []int64{8, 9}) || res.More || res.Head != keyset.EncodeInt64Cursor(8) {
+		t.Fatalf("unexpected poll: ids=%v %+v", ids, res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package kgorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// CountWindow returns a keyset.WindowCountFunc that counts the rows in the
+// window scope applies for a page, on a cloned session of db with the
+// LIMIT removed.
+func CountWindow(db *gorm.DB, scope Scope) keyset.WindowCountFunc {
+	return func(ctx context.Context, p keyset.Page) (int64, error) {
+		var n int64
+		err := scope(db.Session(&gorm.Session{Context: ctx}), p).Limit(-1).Count(&n).Error
+		return n, err
+	}
+}
+
+// Locate sets res.Position and res.Remaining (see keyset.Locate) using the
+// same scope as the page query. The context is taken from db.
+func Locate[T any](db *gorm.DB, res *keyset.Result[T], scope Scope) error {
+	return keyset.Locate(statementContext(db), res, CountWindow(db, scope))
+}
@@ -0,0 +1,41 @@
+package kgorm_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	// Rows before the first item: the DirPrev window of PrevCursor, without LIMIT.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "posts" WHERE id < $1`)).
+		WithArgs(int64(201)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(200))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "posts" WHERE id > $1`)).
+		WithArgs(int64(250)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1000))
+
+	res := keyset.Result[Post]{
+		Items:      []Post{{ID: 201}, {ID: 250}},
+		PrevCursor: keyset.EncodeInt64Cursor(201),
+		NextCursor: keyset.EncodeInt64Cursor(250),
+		HasPrev:    true,
+		HasNext:    true,
+	}
+	if err := kgorm.Locate(db.Model(&Post{}), &res, scopeByID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Position != 201 || res.Remaining != 1000 {
+		t.Fatalf("want position 201 remaining 1000, got %d/%d", res.Position, res.Remaining)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
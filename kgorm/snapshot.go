@@ -0,0 +1,14 @@
+package kgorm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// SnapshotCursor returns the cursor of the record with the largest key for
+// use as keyset.Page.Snapshot (see keyset.SnapshotCursor). ord is the base
+// order scope is used with. The context is taken from db.
+func SnapshotCursor[T any](db *gorm.DB, ord keyset.Order, scope Scope, cursor keyset.CursorFunc[T]) (string, error) {
+	return keyset.SnapshotCursor(statementContext(db), ord, Fetch[T](db, scope), cursor)
+}
@@ -0,0 +1,24 @@
+package kgorm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// SyncFetch returns a keyset.FetchFunc for keyset.Syncer. It pages through
+// db Unscoped, so soft-deleted records (gorm.DeletedAt) are returned as
+// tombstones, in ascending (changedCol, idCol) order:
+//
+//	s := keyset.Syncer[Note]{
+//		Fetch:   kgorm.SyncFetch[Note](db.Model(&Note{}), "COALESCE(deleted_at, updated_at)", "id"),
+//		Key:     noteKey, // DeletedAt.Time if deleted, else UpdatedAt; and ID
+//		Deleted: func(n Note) bool { return n.DeletedAt.Valid },
+//	}
+//
+// changedCol may be an expression; index it to keep syncs cheap.
+func SyncFetch[T any](db *gorm.DB, changedCol, idCol string) keyset.FetchFunc[T] {
+	return Fetch[T](db.Unscoped(), func(db *gorm.DB, p keyset.Page) *gorm.DB {
+		return PageByTimeAndID(db, p, keyset.Ascending, changedCol, idCol)
+	})
+}
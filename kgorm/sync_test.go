@@ -0,0 +1,55 @@
+package kgorm_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgorm"
+)
+
+type Note struct {
+	ID        int64 `gorm:"primaryKey"`
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func TestSyncFetch_IncludesSoftDeleted(t *testing.T) {
+	t.Parallel()
+	db, mock := openMock(t)
+
+	now := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "notes" WHERE COALESCE(deleted_at, updated_at) <= $1 ORDER BY COALESCE(deleted_at, updated_at) ASC, id ASC LIMIT $2`)).
+		WithArgs(now, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at", "deleted_at"}).
+			AddRow(1, now.Add(-time.Hour), nil).
+			AddRow(2, now.Add(-2*time.Hour), now.Add(-time.Minute)))
+
+	s := keyset.Syncer[Note]{
+		Fetch: kgorm.SyncFetch[Note](db.Model(&Note{}), "COALESCE(deleted_at, updated_at)", "id"),
+		Key: func(n Note) (time.Time, int64) {
+			if n.DeletedAt.Valid {
+				return n.DeletedAt.Time, n.ID
+			}
+			return n.UpdatedAt, n.ID
+		},
+		Deleted: func(n Note) bool { return n.DeletedAt.Valid },
+		Limit:   10,
+		Now:     func() time.Time { return now },
+	}
+	res, err := s.Sync(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Upserts) != 1 || res.Upserts[0].ID != 1 || len(res.Tombstones) != 1 || res.Tombstones[0].ID != 2 || !res.CaughtUp {
+		t.Fatalf("unexpected sync result: %+v", res)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
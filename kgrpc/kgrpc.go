@@ -0,0 +1,165 @@
+// Package kgrpc implements Google AIP-158 pagination (page_size, page_token,
+// next_page_token) on top of keyset pagination for gRPC services.
+//
+// It works on plain field values, so it needs no generated protos:
+//
+//	var pager = kgrpc.Pager{DefaultSize: 50, MaxSize: 1000}
+//
+//	func (s *server) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
+//		params := []string{req.GetParent(), req.GetFilter(), req.GetOrderBy()}
+//		page, err := pager.Page(req.GetPageSize(), req.GetPageToken(), params...)
+//		if err != nil {
+//			return nil, err // codes.InvalidArgument
+//		}
+//		res, err := ksql.Paginate(ctx, s.db, page, build, scanBook, bookCursor)
+//		if err != nil {
+//			return nil, kgrpc.Status(err)
+//		}
+//		return &pb.ListBooksResponse{Books: res.Items, NextPageToken: kgrpc.NextPageToken(res, params...)}, nil
+//	}
+package kgrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// ErrInvalidToken is returned (wrapped in an INVALID_ARGUMENT status) for
+// page tokens that are malformed or were issued for different request parameters.
+var ErrInvalidToken = errors.New("kgrpc: invalid page_token")
+
+// bindingSize is the length of the request-parameter fingerprint that
+// prefixes every page token.
+const bindingSize = 8
+
+// Pager converts AIP-158 request fields into keyset.Pages.
+type Pager struct {
+	DefaultSize int32 // Page size when page_size is 0; 0 leaves it to Page.EnsureDefaults
+	MaxSize     int32 // Larger page sizes are coerced down to it, as AIP-158 requires; 0 means no maximum
+}
+
+// Page returns the page for a List request. params are the values of every
+// other request field that affects the result, such as parent, filter and
+// order_by; a token is only accepted together with the params it was issued
+// for. page_size may change between calls, as AIP-158 allows.
+//
+// A negative page_size, a malformed page_token or one issued for different
+// params is reported as a status error with codes.InvalidArgument.
+func (pg Pager) Page(pageSize int32, pageToken string, params ...string) (keyset.Page, error) {
+	if pageSize < 0 {
+		return keyset.Page{}, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	size := pageSize
+	if size == 0 {
+		size = pg.DefaultSize
+	}
+	if pg.MaxSize > 0 && size > pg.MaxSize {
+		size = pg.MaxSize
+	}
+	p := keyset.Page{Dir: keyset.DirNext, Limit: int(size)}
+	if pageToken != "" {
+		cursor, err := decodeToken(pageToken, params)
+		if err != nil {
+			return keyset.Page{}, Status(err)
+		}
+		p.Cursor = cursor
+		if err := p.Validate(); err != nil {
+			return keyset.Page{}, Status(fmt.Errorf("%w: %w", ErrInvalidToken, err))
+		}
+	}
+	if p.Limit == 0 && pg.MaxSize > 0 {
+		// The size will come from the token or Page.EnsureDefaults, which
+		// must not bypass MaxSize either.
+		d := p
+		d.EnsureDefaults()
+		if d.Limit > int(pg.MaxSize) {
+			p.Limit = int(pg.MaxSize)
+		}
+	}
+	return p, nil
+}
+
+// NextPageToken returns the next_page_token for res, bound to params
+// (see Pager.Page). It returns "" on the last page, as AIP-158 requires.
+// The token carries res's snapshot, if any.
+func NextPageToken[T any](res keyset.Result[T], params ...string) string {
+	next := res.NextToken(0)
+	if next == "" {
+		return ""
+	}
+	b := binding(params)
+	return base64.RawURLEncoding.EncodeToString(append(b[:], next...))
+}
+
+// TotalSize returns the total_size for res, or 0 when it carries no total.
+func TotalSize[T any](res keyset.Result[T]) int32 {
+	if res.Total == nil || res.Total.Count > 1<<31-1 {
+		return 0
+	}
+	return int32(res.Total.Count)
+}
+
+// Status converts err into a gRPC status error: ErrInvalidToken and
+// keyset.ErrInvalidPage become INVALID_ARGUMENT, context errors CANCELLED and
+// DEADLINE_EXCEEDED, and anything else INTERNAL with a generic message, so
+// internal details do not leak to clients. Status errors and nil pass through
+// unchanged.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, keyset.ErrInvalidPage):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// decodeToken checks the binding of a page token against params and returns
+// the keyset token (or, for cursors a Token cannot wrap, the cursor) inside.
+func decodeToken(s string, params []string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) <= bindingSize {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	if want := binding(params); string(b[:bindingSize]) != string(want[:]) {
+		return "", fmt.Errorf("%w: request parameters changed since the token was issued", ErrInvalidToken)
+	}
+	next := string(b[bindingSize:])
+	if t, err := keyset.DecodeToken(next); err == nil && t.Dir != keyset.DirNext {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	return next, nil
+}
+
+// binding fingerprints the request parameters a token is tied to.
+// Each value is length-prefixed so that ("ab", "c") and ("a", "bc") differ.
+func binding(params []string) [bindingSize]byte {
+	h := sha256.New()
+	var n [8]byte
+	for _, p := range params {
+		binary.BigEndian.PutUint64(n[:], uint64(len(p)))
+		h.Write(n[:])
+		h.Write([]byte(p))
+	}
+	var out [bindingSize]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
@@ -0,0 +1,136 @@
+package kgrpc_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/kgrpc"
+)
+
+func TestPager_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pager := kgrpc.Pager{DefaultSize: 20, MaxSize: 100}
+	p, err := pager.Page(0, "", "shelves/1", "author=x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Limit != 20 || p.Cursor != "" || p.Dir != keyset.DirNext {
+		t.Fatalf("first page mismatch: %+v", p)
+	}
+
+	res := keyset.Result[int64]{Items: []int64{1, 2}, NextCursor: keyset.EncodeInt64Cursor(2), HasNext: true, Snapshot: keyset.EncodeInt64Cursor(9)}
+	token := kgrpc.NextPageToken(res, "shelves/1", "author=x")
+	if token == "" {
+		t.Fatal("want a next_page_token")
+	}
+
+	p, err = pager.Page(500, token, "shelves/1", "author=x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Limit != 100 {
+		t.Fatalf("page_size must be coerced to the maximum, got %d", p.Limit)
+	}
+	p.EnsureDefaults()
+	if p.Cursor != res.NextCursor || p.Dir != keyset.DirNext || p.Snapshot != res.Snapshot {
+		t.Fatalf("token must continue after the last item: %+v", p)
+	}
+
+	if got := kgrpc.NextPageToken(keyset.Result[int64]{Items: []int64{1}, NextCursor: keyset.EncodeInt64Cursor(1)}); got != "" {
+		t.Fatalf("last page must have an empty next_page_token, got %q", got)
+	}
+}
+
+func TestPager_MaxSizeWithoutPageSize(t *testing.T) {
+	t.Parallel()
+
+	// Swap the keyset token inside a real page token for one carrying a huge limit.
+	res := keyset.Result[int64]{NextCursor: keyset.EncodeInt64Cursor(2), HasNext: true}
+	raw, _ := base64.RawURLEncoding.DecodeString(kgrpc.NextPageToken(res, "shelves/1"))
+	crafted := append(raw[:8:8], keyset.NextToken(res.NextCursor, 1<<31)...)
+
+	pager := kgrpc.Pager{MaxSize: 10}
+	for _, token := range []string{"", base64.RawURLEncoding.EncodeToString(crafted)} {
+		p, err := pager.Page(0, token, "shelves/1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.EnsureDefaults()
+		if p.Limit != 10 {
+			t.Fatalf("token %q: size must be coerced to the maximum, got %d", token, p.Limit)
+		}
+	}
+}
+
+func TestPager_InvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	res := keyset.Result[int64]{NextCursor: keyset.EncodeInt64Cursor(2), HasNext: true}
+	token := kgrpc.NextPageToken(res, "shelves/1", "author=x")
+	cases := []struct {
+		name   string
+		size   int32
+		token  string
+		params []string
+	}{
+		{"negative size", -1, "", nil},
+		{"garbage token", 10, "%%%", nil},
+		{"filter changed", 10, token, []string{"shelves/1", "author=y"}},
+		{"params shifted", 10, token, []string{"shelves/1author=x", ""}},
+		{"params dropped", 10, token, nil},
+		{"plain cursor", 10, keyset.EncodeInt64Cursor(2), []string{"shelves/1", "author=x"}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := kgrpc.Pager{}.Page(c.size, c.token, c.params...)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("want INVALID_ARGUMENT, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{nil, codes.OK},
+		{kgrpc.ErrInvalidToken, codes.InvalidArgument},
+		{keyset.ErrInvalidPage, codes.InvalidArgument},
+		{context.Canceled, codes.Canceled},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.NotFound, "no shelf"), codes.NotFound},
+		{errors.New("db is down"), codes.Internal},
+	}
+	for _, c := range cases {
+		if got := status.Code(kgrpc.Status(c.err)); got != c.want {
+			t.Fatalf("%v: want %v, got %v", c.err, c.want, got)
+		}
+	}
+	if msg := status.Convert(kgrpc.Status(errors.New("dial tcp 10.0.0.5:5432: refused"))).Message(); msg != "internal error" {
+		t.Fatalf("internal errors must not leak details, got %q", msg)
+	}
+}
+
+func TestTotalSize(t *testing.T) {
+	t.Parallel()
+
+	if got := kgrpc.TotalSize(keyset.Result[int64]{Total: &keyset.Total{Count: 42}}); got != 42 {
+		t.Fatalf("want 42, got %d", got)
+	}
+	if got := kgrpc.TotalSize(keyset.Result[int64]{}); got != 0 {
+		t.Fatalf("want 0 without a total, got %d", got)
+	}
+}
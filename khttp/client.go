@@ -0,0 +1,275 @@
+package khttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Next identifies the page a FetchFunc should load: by the envelope's
+// NextCursor, or by the URL of a Link header. Both are empty for the first page.
+type Next struct {
+	Cursor string
+	URL    string
+}
+
+// FetchFunc loads one page of a keyset-paginated API.
+type FetchFunc[T any] func(ctx context.Context, next Next) (Envelope[T], error)
+
+// Follower walks every page of a paginated API, following NextCursor or,
+// when the envelope has none, Links.Next (e.g. from a Link header).
+// Failed fetches are retried with exponential backoff.
+type Follower[T any] struct {
+	Fetch FetchFunc[T]
+
+	// Retries is the number of retries per page; 0 means 3, a negative value none.
+	Retries int
+
+	// Backoff returns the delay before retry n (starting at 1). The default
+	// doubles from 100ms up to 10s. A longer StatusError.RetryAfter wins.
+	Backoff func(n int) time.Duration
+
+	// Retryable reports whether a failed fetch is worth retrying.
+	// The default is Retryable.
+	Retryable func(err error) bool
+}
+
+// All returns an iterator over the items of every page. It stops after
+// the first error, which is yielded with the zero T.
+func (f Follower[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for page, err := range f.Pages(ctx) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Pages returns an iterator over the envelopes of every page.
+// It stops after the first error, which is yielded with the zero Envelope.
+func (f Follower[T]) Pages(ctx context.Context) iter.Seq2[Envelope[T], error] {
+	return func(yield func(Envelope[T], error) bool) {
+		var next Next
+		for {
+			e, err := f.fetch(ctx, next)
+			if err != nil {
+				yield(Envelope[T]{}, err)
+				return
+			}
+			if !yield(e, nil) {
+				return
+			}
+			prev := next
+			switch {
+			case e.NextCursor != "":
+				next = Next{Cursor: e.NextCursor}
+			case e.Links.Next != "":
+				next = Next{URL: e.Links.Next}
+			default:
+				return
+			}
+			if next == prev {
+				yield(Envelope[T]{}, fmt.Errorf("khttp: next page %+v repeats the current one", next))
+				return
+			}
+		}
+	}
+}
+
+// fetch loads one page, retrying failures.
+func (f Follower[T]) fetch(ctx context.Context, next Next) (Envelope[T], error) {
+	retries := f.Retries
+	if retries == 0 {
+		retries = 3
+	}
+	retryable := f.Retryable
+	if retryable == nil {
+		retryable = Retryable
+	}
+	for n := 1; ; n++ {
+		e, err := f.Fetch(ctx, next)
+		if err == nil || n > retries || !retryable(err) || ctx.Err() != nil {
+			return e, err
+		}
+		delay := f.backoff(n)
+		var se *StatusError
+		if errors.As(err, &se) && se.RetryAfter > delay {
+			delay = se.RetryAfter
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return Envelope[T]{}, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (f Follower[T]) backoff(n int) time.Duration {
+	if f.Backoff != nil {
+		return f.Backoff(n)
+	}
+	d := 100 * time.Millisecond << min(n-1, 7)
+	return min(d, 10*time.Second)
+}
+
+// Retryable reports whether err is a transient failure: a StatusError with
+// status 408, 429 or 5xx, or a network error. Context errors are not retryable.
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusRequestTimeout || se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// StatusError is returned by NewFetch for non-2xx responses.
+type StatusError struct {
+	Code       int
+	Body       []byte        // Beginning of the response body
+	RetryAfter time.Duration // From a Retry-After header given in seconds
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("khttp: unexpected status %d: %s", e.Code, bytes.TrimSpace(e.Body))
+}
+
+// NewFetch returns a FetchFunc that GETs JSON pages from rawURL with client
+// (http.DefaultClient if nil). Cursors are sent in the cursor parameter named
+// by b, replacing any in rawURL; Link URLs are requested as given, resolved
+// against rawURL.
+//
+// The body is decoded as an Envelope of any Style, or as a bare JSON array
+// of items. Links missing from the body are taken from the Link header.
+func NewFetch[T any](client *http.Client, rawURL string, b Binder) (FetchFunc[T], error) {
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	names := b.Params.withDefaults()
+
+	return func(ctx context.Context, next Next) (Envelope[T], error) {
+		u := *base
+		switch {
+		case next.URL != "":
+			ref, err := url.Parse(next.URL)
+			if err != nil {
+				return Envelope[T]{}, fmt.Errorf("khttp: next link: %w", err)
+			}
+			u = *base.ResolveReference(ref)
+		case next.Cursor != "":
+			q := u.Query()
+			q.Del(names.After)
+			q.Del(names.Before)
+			q.Set(names.Cursor, next.Cursor)
+			u.RawQuery = q.Encode()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return Envelope[T]{}, err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return Envelope[T]{}, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			se := &StatusError{Code: resp.StatusCode, Body: body}
+			if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+				se.RetryAfter = time.Duration(s) * time.Second
+			}
+			return Envelope[T]{}, se
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Envelope[T]{}, err
+		}
+		var e Envelope[T]
+		if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(trimmed, &e.Data)
+		} else {
+			err = json.Unmarshal(body, &e)
+		}
+		if err != nil {
+			return Envelope[T]{}, fmt.Errorf("khttp: decode page: %w", err)
+		}
+		if e.Links == (Links{}) {
+			e.Links = ParseLinks(resp.Header.Values("Link")...)
+			e.HasMore = e.HasMore || e.Links.Next != ""
+		}
+		return e, nil
+	}, nil
+}
+
+// ParseLinks extracts the next, prev, first and last URLs from RFC 8288
+// Link header values, the inverse of Links.String. Other relations are ignored.
+func ParseLinks(headers ...string) Links {
+	var l Links
+	for _, h := range headers {
+		for h != "" {
+			start := strings.IndexByte(h, '<')
+			end := strings.IndexByte(h, '>')
+			if start < 0 || end < start {
+				break
+			}
+			target := h[start+1 : end]
+			h = h[end+1:]
+
+			params := h
+			if i := strings.IndexByte(h, ','); i >= 0 {
+				params, h = h[:i], h[i+1:]
+			} else {
+				h = ""
+			}
+			for _, p := range strings.Split(params, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+				if !ok || !strings.EqualFold(k, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(v, `"`)) {
+					switch strings.ToLower(rel) {
+					case "next":
+						l.Next = target
+					case "prev", "previous":
+						l.Prev = target
+					case "first":
+						l.First = target
+					case "last":
+						l.Last = target
+					}
+				}
+			}
+		}
+	}
+	return l
+}
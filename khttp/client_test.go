@@ -0,0 +1,164 @@
+package khttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+// serveItems serves ids 1..n in pages through Binder, Paginate and Envelope.
+func serveItems(n int64, style khttp.Style) http.HandlerFunc {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	fetch := func(_ context.Context, p keyset.Page) ([]item, error) {
+		after, _ := keyset.DecodeInt64Cursor(p.Cursor)
+		var out []item
+		for _, id := range ids {
+			if id > after && len(out) < p.Limit {
+				out = append(out, item{id})
+			}
+		}
+		return out, nil
+	}
+	cursor := func(it item) string { return keyset.EncodeInt64Cursor(it.ID) }
+	binder := khttp.Binder{Limit: khttp.LimitPolicy{Default: 2}}
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := binder.Bind(r)
+		if err != nil {
+			khttp.WriteError(w, err)
+			return
+		}
+		res, err := keyset.Paginate(r.Context(), p, fetch, cursor)
+		if err != nil {
+			khttp.WriteError(w, err)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(khttp.NewEnvelope(res, style))
+	}
+}
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestFollower_Envelope(t *testing.T) {
+	t.Parallel()
+
+	var failures atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the second page once.
+		if r.URL.Query().Get("cursor") != "" && failures.Add(1) == 1 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		serveItems(5, khttp.StyleCamel)(w, r)
+	}))
+	defer srv.Close()
+
+	fetch, err := khttp.NewFetch[item](srv.Client(), srv.URL+"/items", khttp.Binder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int64
+	for it, err := range (khttp.Follower[item]{Fetch: fetch, Backoff: noBackoff}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, it.ID)
+	}
+	if want := []int64{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestFollower_LinkHeaders(t *testing.T) {
+	t.Parallel()
+
+	// A GitHub-style API: bare arrays, pages advertised in Link headers only.
+	pages := [][]item{{{1}, {2}}, {{3}, {4}}, {{5}}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if n+1 < len(pages) {
+			w.Header().Set("Link", `</repos?page=`+strconv.Itoa(n+1)+`>; rel="next", </repos?page=2>; rel="last"`)
+		}
+		_ = json.NewEncoder(w).Encode(pages[n])
+	}))
+	defer srv.Close()
+
+	fetch, err := khttp.NewFetch[item](nil, srv.URL+"/repos", khttp.Binder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int64
+	for it, err := range (khttp.Follower[item]{Fetch: fetch}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, it.ID)
+	}
+	if want := []int64{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestFollower_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Get("limit") == "bad" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		query string
+		calls int32
+		code  int
+	}{
+		{"?limit=bad", 1, http.StatusBadRequest}, // not retryable
+		{"", 3, http.StatusBadGateway},           // 1 try + 2 retries
+	}
+	for _, c := range cases {
+		calls.Store(0)
+		fetch, _ := khttp.NewFetch[item](nil, srv.URL+c.query, khttp.Binder{})
+		var err error
+		for _, err = range (khttp.Follower[item]{Fetch: fetch, Retries: 2, Backoff: noBackoff}).All(context.Background()) {
+		}
+		var se *khttp.StatusError
+		if !errors.As(err, &se) || se.Code != c.code {
+			t.Fatalf("want status %d, got %v", c.code, err)
+		}
+		if got := calls.Load(); got != c.calls {
+			t.Fatalf("want %d calls, got %d", c.calls, got)
+		}
+	}
+}
+
+func TestParseLinks(t *testing.T) {
+	t.Parallel()
+
+	want := khttp.Links{Next: "https://x/?a=1,2&cursor=n", Prev: "/p", First: "/f", Last: "/l"}
+	got := khttp.ParseLinks(want.String())
+	if got != want {
+		t.Fatalf("round trip mismatch:\nwant %+v\ngot  %+v", want, got)
+	}
+	got = khttp.ParseLinks(`<https://x/2>; rel="next last"; title="x"`, `<https://x/0>; REL=prev`)
+	if got.Next != "https://x/2" || got.Last != "https://x/2" || got.Prev != "https://x/0" {
+		t.Fatalf("unexpected links: %+v", got)
+	}
+}
@@ -0,0 +1,167 @@
+package khttp
+
+import (
+	"encoding/json"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Style selects the field naming of an Envelope.
+type Style int
+
+const (
+	StyleSnake   Style = iota // {"data", "next_cursor", "prev_cursor", "has_more", "has_prev", "total"}
+	StyleCamel                // {"data", "nextCursor", "prevCursor", "hasMore", "hasPrev", "total"}
+	StyleJSONAPI              // {"data", "links": {"next", ...}, "meta": {"nextCursor", ...}}
+)
+
+// Envelope is the JSON body of a page response. It marshals in its Style
+// and unmarshals any style, so API clients can decode responses with the
+// same type and follow NextCursor.
+type Envelope[T any] struct {
+	Data           []T
+	NextCursor     string // Token for the next page; "" on the last page
+	PrevCursor     string // Token for the previous page; "" on the first page
+	HasMore        bool   // Whether a next page exists
+	HasPrev        bool   // Whether a previous page exists
+	Total          *int64 // Total row count, if requested
+	TotalCapped    bool   // Total is a lower bound (see keyset.Total.Capped)
+	TotalEstimated bool   // Total is a planner estimate (see keyset.CountEstimate)
+	Links          Links  // Page URLs; written for StyleJSONAPI only
+	Style          Style  // Field naming used by MarshalJSON; set by UnmarshalJSON
+}
+
+// NewEnvelope returns the envelope for res in the given style. The cursors
+// are the result's page tokens (see keyset.Result.NextToken), which clients
+// send back as the only parameter. For StyleJSONAPI set Links as well,
+// e.g. from NewLinks.
+func NewEnvelope[T any](res keyset.Result[T], style Style) Envelope[T] {
+	e := Envelope[T]{
+		Data:       res.Items,
+		NextCursor: res.NextToken(0),
+		PrevCursor: res.PrevToken(0),
+		HasMore:    res.HasNext,
+		HasPrev:    res.HasPrev,
+		Style:      style,
+	}
+	if res.Total != nil {
+		n := res.Total.Count
+		e.Total = &n
+		e.TotalCapped = res.Total.Capped
+		e.TotalEstimated = res.Total.Mode == keyset.CountEstimate
+	}
+	return e
+}
+
+type snakeEnvelope[T any] struct {
+	Data           []T    `json:"data"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+	PrevCursor     string `json:"prev_cursor,omitempty"`
+	HasMore        bool   `json:"has_more"`
+	HasPrev        bool   `json:"has_prev"`
+	Total          *int64 `json:"total,omitempty"`
+	TotalCapped    bool   `json:"total_capped,omitempty"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"`
+}
+
+type camelEnvelope[T any] struct {
+	Data           []T    `json:"data"`
+	NextCursor     string `json:"nextCursor,omitempty"`
+	PrevCursor     string `json:"prevCursor,omitempty"`
+	HasMore        bool   `json:"hasMore"`
+	HasPrev        bool   `json:"hasPrev"`
+	Total          *int64 `json:"total,omitempty"`
+	TotalCapped    bool   `json:"totalCapped,omitempty"`
+	TotalEstimated bool   `json:"totalEstimated,omitempty"`
+}
+
+type jsonAPIEnvelope[T any] struct {
+	Data  []T           `json:"data"`
+	Links *jsonAPILinks `json:"links,omitempty"`
+	Meta  jsonAPIMeta   `json:"meta"`
+}
+
+type jsonAPILinks struct {
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+type jsonAPIMeta struct {
+	NextCursor     string `json:"nextCursor,omitempty"`
+	PrevCursor     string `json:"prevCursor,omitempty"`
+	HasMore        bool   `json:"hasMore"`
+	HasPrev        bool   `json:"hasPrev"`
+	Total          *int64 `json:"total,omitempty"`
+	TotalCapped    bool   `json:"totalCapped,omitempty"`
+	TotalEstimated bool   `json:"totalEstimated,omitempty"`
+}
+
+// MarshalJSON encodes e in e.Style. A nil Data is written as [].
+func (e Envelope[T]) MarshalJSON() ([]byte, error) {
+	data := e.Data
+	if data == nil {
+		data = []T{}
+	}
+	switch e.Style {
+	case StyleCamel:
+		return json.Marshal(camelEnvelope[T]{data, e.NextCursor, e.PrevCursor, e.HasMore, e.HasPrev, e.Total, e.TotalCapped, e.TotalEstimated})
+	case StyleJSONAPI:
+		out := jsonAPIEnvelope[T]{Data: data, Meta: jsonAPIMeta{e.NextCursor, e.PrevCursor, e.HasMore, e.HasPrev, e.Total, e.TotalCapped, e.TotalEstimated}}
+		if e.Links != (Links{}) {
+			l := jsonAPILinks(e.Links)
+			out.Links = &l
+		}
+		return json.Marshal(out)
+	default:
+		return json.Marshal(snakeEnvelope[T]{data, e.NextCursor, e.PrevCursor, e.HasMore, e.HasPrev, e.Total, e.TotalCapped, e.TotalEstimated})
+	}
+}
+
+// UnmarshalJSON decodes an envelope of any Style and records the style found:
+// StyleJSONAPI when "meta" or "links" is present, StyleCamel when a camelCase
+// pagination field is present, and StyleSnake otherwise.
+func (e *Envelope[T]) UnmarshalJSON(b []byte) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return err
+	}
+	_, meta := keys["meta"]
+	_, links := keys["links"]
+	switch {
+	case meta || links:
+		var in jsonAPIEnvelope[T]
+		if err := json.Unmarshal(b, &in); err != nil {
+			return err
+		}
+		m := in.Meta
+		*e = Envelope[T]{in.Data, m.NextCursor, m.PrevCursor, m.HasMore, m.HasPrev, m.Total, m.TotalCapped, m.TotalEstimated, Links{}, StyleJSONAPI}
+		if in.Links != nil {
+			e.Links = Links(*in.Links)
+		}
+	case hasAny(keys, "nextCursor", "prevCursor", "hasMore", "hasPrev", "totalCapped", "totalEstimated"):
+		var in camelEnvelope[T]
+		if err := json.Unmarshal(b, &in); err != nil {
+			return err
+		}
+		*e = Envelope[T]{in.Data, in.NextCursor, in.PrevCursor, in.HasMore, in.HasPrev, in.Total, in.TotalCapped, in.TotalEstimated, Links{}, StyleCamel}
+	default:
+		var in snakeEnvelope[T]
+		if err := json.Unmarshal(b, &in); err != nil {
+			return err
+		}
+		*e = Envelope[T]{in.Data, in.NextCursor, in.PrevCursor, in.HasMore, in.HasPrev, in.Total, in.TotalCapped, in.TotalEstimated, Links{}, StyleSnake}
+	}
+	return nil
+}
+
+// hasAny reports whether keys contains any of names.
+func hasAny(keys map[string]json.RawMessage, names ...string) bool {
+	for _, n := range names {
+		if _, ok := keys[n]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package khttp_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+type item struct {
+	ID int64 `json:"id"`
+}
+
+func TestEnvelope_Marshal(t *testing.T) {
+	t.Parallel()
+
+	res := keyset.Result[item]{
+		Items:      []item{{1}, {2}},
+		PrevCursor: keyset.EncodeInt64Cursor(1),
+		NextCursor: keyset.EncodeInt64Cursor(2),
+		HasNext:    true,
+		Total:      &keyset.Total{Count: 5, Mode: keyset.CountExact},
+	}
+	next := res.NextToken(0)
+	cases := []struct {
+		style khttp.Style
+		want  string
+	}{
+		{khttp.StyleSnake, `{"data":[{"id":1},{"id":2}],"next_cursor":"` + next + `","has_more":true,"has_prev":false,"total":5}`},
+		{khttp.StyleCamel, `{"data":[{"id":1},{"id":2}],"nextCursor":"` + next + `","hasMore":true,"hasPrev":false,"total":5}`},
+		{khttp.StyleJSONAPI, `{"data":[{"id":1},{"id":2}],"links":{"next":"/posts?cursor=x"},"meta":{"nextCursor":"` + next + `","hasMore":true,"hasPrev":false,"total":5}}`},
+	}
+	for _, c := range cases {
+		e := khttp.NewEnvelope(res, c.style)
+		if c.style == khttp.StyleJSONAPI {
+			e.Links = khttp.Links{Next: "/posts?cursor=x"}
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(b) != c.want {
+			t.Fatalf("style %d:\nwant %s\ngot  %s", c.style, c.want, b)
+		}
+	}
+
+	b, _ := json.Marshal(khttp.Envelope[item]{})
+	if string(b) != `{"data":[],"has_more":false,"has_prev":false}` {
+		t.Fatalf("empty envelope must have empty data: %s", b)
+	}
+
+	res.Total = &keyset.Total{Count: 5000, Mode: keyset.CountEstimate}
+	b, _ = json.Marshal(khttp.NewEnvelope(res, khttp.StyleSnake))
+	if want := `"total":5000,"total_estimated":true}`; !strings.HasSuffix(string(b), want) {
+		t.Fatalf("estimated total must be marked, want suffix %s, got %s", want, b)
+	}
+}
+
+func TestEnvelope_UnmarshalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	total := int64(10001)
+	for _, style := range []khttp.Style{khttp.StyleSnake, khttp.StyleCamel, khttp.StyleJSONAPI} {
+		in := khttp.Envelope[item]{
+			Data:           []item{{3}, {4}},
+			NextCursor:     "n",
+			PrevCursor:     "p",
+			HasMore:        true,
+			HasPrev:        true,
+			Total:          &total,
+			TotalCapped:    true,
+			TotalEstimated: true,
+			Style:          style,
+		}
+		if style == khttp.StyleJSONAPI {
+			in.Links = khttp.Links{Next: "/next", First: "/first"}
+		}
+		b, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out khttp.Envelope[item]
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Style != style || !slices.Equal(out.Data, in.Data) || out.NextCursor != "n" || out.PrevCursor != "p" ||
+			!out.HasMore || !out.HasPrev || out.Total == nil || *out.Total != total || !out.TotalCapped || !out.TotalEstimated || out.Links != in.Links {
+			t.Fatalf("style %d round trip mismatch: %+v from %s", style, out, b)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package khttp binds keyset pagination to net/http.
+//
+// It builds a keyset.Page from the query parameters of a request, validates
+// it against a limit policy and reports bad input as structured 400 errors.
+//
+// Example:
+//
+//	var binder = khttp.Binder{Limit: khttp.LimitPolicy{Default: 20, Max: 100}}
+//
+//	func listPosts(w http.ResponseWriter, r *http.Request) {
+//		page, err := binder.Bind(r)
+//		if err != nil {
+//			khttp.WriteError(w, err)
+//			return
+//		}
+//		// query with ksql or kgorm using page...
+//	}
+package khttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Params names the query parameters read by a Binder.
+// Empty fields fall back to DefaultParams.
+type Params struct {
+	Cursor string // Opaque cursor or page token, e.g. "cursor"
+	Limit  string // Page size, e.g. "limit"
+	Dir    string // Direction: next, prev, first or last, e.g. "dir"
+	After  string // Cursor shorthand for dir=next, e.g. "after"
+	Before string // Cursor shorthand for dir=prev, e.g. "before"
+	Sort   string // Sort field, "-" prefixed for descending, e.g. "sort"
+}
+
+// DefaultParams are the parameter names used when Binder.Params leaves them unset.
+var DefaultParams = Params{
+	Cursor: "cursor",
+	Limit:  "limit",
+	Dir:    "dir",
+	After:  "after",
+	Before: "before",
+	Sort:   "sort",
+}
+
+// withDefaults fills empty names from DefaultParams.
+func (p Params) withDefaults() Params {
+	or := func(s, def string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	}
+	return Params{
+		Cursor: or(p.Cursor, DefaultParams.Cursor),
+		Limit:  or(p.Limit, DefaultParams.Limit),
+		Dir:    or(p.Dir, DefaultParams.Dir),
+		After:  or(p.After, DefaultParams.After),
+		Before: or(p.Before, DefaultParams.Before),
+		Sort:   or(p.Sort, DefaultParams.Sort),
+	}
+}
+
+// LimitPolicy bounds the page size clients may request.
+type LimitPolicy struct {
+	Default int  // Used when no limit is given; 0 leaves it to Page.EnsureDefaults
+	Max     int  // Largest accepted limit; 0 means no upper bound
+	Clamp   bool // Lower limits above Max to Max instead of rejecting them
+}
+
+// Binder builds keyset.Pages from query parameters.
+// The zero value uses DefaultParams and accepts any positive limit.
+type Binder struct {
+	Params Params
+	Limit  LimitPolicy
+	Sort   SortPolicy
+
+	// CheckCursor optionally validates the key cursor, e.g. by decoding it with
+	// keyset.DecodeTimeAndInt64Cursor, so malformed cursors are rejected with
+	// a 400 instead of failing later in the query. Tokens are unwrapped first.
+	CheckCursor func(cursor string) error
+}
+
+// Bind builds a Page from the query of r using the default Binder.
+func Bind(r *http.Request) (keyset.Page, error) {
+	return Binder{}.Bind(r)
+}
+
+// Bind builds a Page from the query of r (see BindValues).
+func (b Binder) Bind(r *http.Request) (keyset.Page, error) {
+	return b.BindValues(r.URL.Query())
+}
+
+// BindValues builds a Page from q.
+//
+// The cursor is read from the cursor parameter, or from after (DirNext) or
+// before (DirPrev); at most one of them may be present. The dir parameter
+// accepts next, prev, first and last. Without either, the page direction is
+// left to the cursor's Token or defaults to DirNext. The cursor is passed on
+// unchanged, so Tokens are unwrapped by Page.EnsureDefaults as usual.
+//
+// LimitPolicy.Max also applies when the limit is taken from a Token or from
+// the default of Page.EnsureDefaults.
+//
+// With SortPolicy.Fields set, the sort parameter is checked against the
+// allowlist as well, so Bind and Middleware reject unknown sorts; handlers
+// read the validated sort with BindSort.
+//
+// All errors are of type *Error.
+func (b Binder) BindValues(q url.Values) (keyset.Page, error) {
+	names := b.Params.withDefaults()
+	var p keyset.Page
+
+	if len(b.Sort.Fields) > 0 {
+		if _, err := b.BindSortValues(q); err != nil {
+			return keyset.Page{}, err
+		}
+	}
+
+	set, from := 0, names.Cursor // from names the parameter the cursor was read from
+	for _, c := range []struct {
+		name string
+		dir  keyset.Dir
+	}{
+		{names.Cursor, 0},
+		{names.After, keyset.DirNext},
+		{names.Before, keyset.DirPrev},
+	} {
+		v, ok := single(q, c.name)
+		if !ok {
+			return keyset.Page{}, &Error{Param: c.name, Message: "must be given at most once"}
+		}
+		if v == "" {
+			continue
+		}
+		if set++; set > 1 {
+			return keyset.Page{}, &Error{Param: c.name, Message: fmt.Sprintf("cannot be combined with %s, %s or %s", names.Cursor, names.After, names.Before)}
+		}
+		p.Cursor, p.Dir, from = v, c.dir, c.name
+	}
+
+	if v, ok := single(q, names.Dir); !ok {
+		return keyset.Page{}, &Error{Param: names.Dir, Message: "must be given at most once"}
+	} else if v != "" {
+		dir, err := ParseDir(v)
+		if err != nil {
+			return keyset.Page{}, &Error{Param: names.Dir, Value: v, Message: err.Error()}
+		}
+		if p.Dir != 0 && p.Dir != dir {
+			return keyset.Page{}, &Error{Param: names.Dir, Value: v, Message: "conflicts with " + from}
+		}
+		p.Dir = dir
+	}
+
+	limit, err := b.limit(q, names.Limit)
+	if err != nil {
+		return keyset.Page{}, err
+	}
+	p.Limit = limit
+	if p.Limit == 0 && b.Limit.Max > 0 {
+		// The limit will come from the cursor's Token or Page.EnsureDefaults,
+		// which must not bypass Max either.
+		d := p
+		d.EnsureDefaults()
+		if d.Limit > b.Limit.Max {
+			if t, err := keyset.DecodeToken(p.Cursor); err == nil && t.Limit > b.Limit.Max && !b.Limit.Clamp {
+				return keyset.Page{}, &Error{Param: from, Value: p.Cursor, Message: fmt.Sprintf("carries a limit above %d", b.Limit.Max)}
+			}
+			p.Limit = b.Limit.Max
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		param := names.Dir
+		if p.Cursor != "" {
+			param = from
+		}
+		return keyset.Page{}, &Error{Param: param, Message: strings.TrimPrefix(err.Error(), keyset.ErrInvalidPage.Error()+": "), Err: err}
+	}
+	if b.CheckCursor != nil && p.Cursor != "" {
+		key := p.Cursor
+		if t, err := keyset.DecodeToken(key); err == nil {
+			key = t.Cursor // Empty for a snapshot-only Token
+		}
+		if key != "" {
+			if err := b.CheckCursor(key); err != nil {
+				return keyset.Page{}, &Error{Param: from, Value: p.Cursor, Message: "malformed cursor", Err: err}
+			}
+		}
+	}
+	return p, nil
+}
+
+// limit parses the limit parameter and applies the limit policy.
+func (b Binder) limit(q url.Values, name string) (int, error) {
+	v, ok := single(q, name)
+	if !ok {
+		return 0, &Error{Param: name, Message: "must be given at most once"}
+	}
+	if v == "" {
+		return b.Limit.Default, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, &Error{Param: name, Value: v, Message: "must be a positive integer"}
+	}
+	if b.Limit.Max > 0 && n > b.Limit.Max {
+		if !b.Limit.Clamp {
+			return 0, &Error{Param: name, Value: v, Message: fmt.Sprintf("must not exceed %d", b.Limit.Max)}
+		}
+		n = b.Limit.Max
+	}
+	return n, nil
+}
+
+// ParseDir parses a direction name: next, prev (or previous), first or last.
+func ParseDir(s string) (keyset.Dir, error) {
+	switch strings.ToLower(s) {
+	case "next":
+		return keyset.DirNext, nil
+	case "prev", "previous":
+		return keyset.DirPrev, nil
+	case "first":
+		return keyset.DirFirst, nil
+	case "last":
+		return keyset.DirLast, nil
+	default:
+		return 0, errors.New("must be one of next, prev, first or last")
+	}
+}
+
+// DirName returns the query value ParseDir accepts for d, or "" for unknown directions.
+func DirName(d keyset.Dir) string {
+	switch d {
+	case keyset.DirNext:
+		return "next"
+	case keyset.DirPrev:
+		return "prev"
+	case keyset.DirFirst:
+		return "first"
+	case keyset.DirLast:
+		return "last"
+	default:
+		return ""
+	}
+}
+
+// single returns the only value of name in q; ok is false if it repeats.
+func single(q url.Values, name string) (v string, ok bool) {
+	vs := q[name]
+	if len(vs) > 1 {
+		return "", false
+	}
+	if len(vs) == 1 {
+		v = vs[0]
+	}
+	return v, true
+}
+
+// Error reports an invalid pagination parameter. It is rendered as a
+// 400 Bad Request by WriteError.
+type Error struct {
+	Param   string // Name of the offending query parameter
+	Value   string // Value as sent, if relevant
+	Message string // Human-readable reason
+	Err     error  // Underlying error, if any
+}
+
+func (e *Error) Error() string {
+	return "khttp: invalid parameter " + strconv.Quote(e.Param) + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns http.StatusBadRequest.
+func (e *Error) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// errorBody is the JSON body written by WriteError.
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// WriteError writes err as a JSON error response:
+//
+//	{"error": {"code": "invalid_parameter", "param": "limit", "value": "0", "message": "must be a positive integer"}}
+//
+// An *Error is written with status 400; any other error with status 500 and
+// a generic message, so internal details do not leak to clients.
+func WriteError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	body := errorBody{Error: errorDetail{Code: "internal", Message: http.StatusText(status)}}
+	var e *Error
+	if errors.As(err, &e) {
+		status = e.StatusCode()
+		body.Error = errorDetail{Code: "invalid_parameter", Param: e.Param, Value: e.Value, Message: e.Message}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
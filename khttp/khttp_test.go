@@ -0,0 +1,163 @@
+package khttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestBinder_BindValues(t *testing.T) {
+	t.Parallel()
+
+	cur := keyset.EncodeInt64Cursor(42)
+	binder := khttp.Binder{Limit: khttp.LimitPolicy{Default: 20, Max: 100}}
+	cases := []struct {
+		name  string
+		query string
+		want  keyset.Page
+	}{
+		{"empty", "", keyset.Page{Limit: 20}},
+		{"cursor", "cursor=" + cur + "&limit=5", keyset.Page{Cursor: cur, Limit: 5}},
+		{"cursor with dir", "cursor=" + cur + "&dir=prev", keyset.Page{Cursor: cur, Dir: keyset.DirPrev, Limit: 20}},
+		{"after", "after=" + cur, keyset.Page{Cursor: cur, Dir: keyset.DirNext, Limit: 20}},
+		{"before", "before=" + cur, keyset.Page{Cursor: cur, Dir: keyset.DirPrev, Limit: 20}},
+		{"before with matching dir", "before=" + cur + "&dir=previous", keyset.Page{Cursor: cur, Dir: keyset.DirPrev, Limit: 20}},
+		{"first", "dir=first", keyset.Page{Dir: keyset.DirFirst, Limit: 20}},
+		{"last", "dir=LAST&limit=100", keyset.Page{Dir: keyset.DirLast, Limit: 100}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			q, _ := url.ParseQuery(c.query)
+			got, err := binder.BindValues(q)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("want %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestBinder_Errors(t *testing.T) {
+	t.Parallel()
+
+	cur := keyset.EncodeInt64Cursor(42)
+	binder := khttp.Binder{
+		Limit: khttp.LimitPolicy{Max: 100},
+		CheckCursor: func(s string) error {
+			_, err := keyset.DecodeInt64Cursor(s)
+			return err
+		},
+	}
+	cases := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"limit not a number", "limit=ten", "limit"},
+		{"limit zero", "limit=0", "limit"},
+		{"limit above max", "limit=101", "limit"},
+		{"limit repeated", "limit=1&limit=2", "limit"},
+		{"unknown dir", "dir=sideways", "dir"},
+		{"prev without cursor", "dir=prev", "dir"},
+		{"first with cursor", "cursor=" + cur + "&dir=first", "cursor"},
+		{"after and before", "after=" + cur + "&before=" + cur, "before"},
+		{"cursor and after", "cursor=" + cur + "&after=" + cur, "after"},
+		{"after with dir prev", "after=" + cur + "&dir=prev", "dir"},
+		{"token for other direction", "after=" + keyset.PrevToken(cur, 10), "after"},
+		{"malformed cursor", "cursor=not-a-cursor", "cursor"},
+		{"malformed cursor with dir", "cursor=not-a-cursor&dir=prev", "cursor"},
+		{"cursor token for other direction", "cursor=" + keyset.NextToken(cur, 10) + "&dir=prev", "cursor"},
+		{"token limit above max", "cursor=" + keyset.NextToken(cur, 1<<31), "cursor"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			q, _ := url.ParseQuery(c.query)
+			_, err := binder.BindValues(q)
+			var e *khttp.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("want *khttp.Error, got %v", err)
+			}
+			if e.Param != c.param {
+				t.Fatalf("want param %q, got %q (%v)", c.param, e.Param, err)
+			}
+		})
+	}
+}
+
+func TestBinder_ClampAndParams(t *testing.T) {
+	t.Parallel()
+
+	binder := khttp.Binder{
+		Params: khttp.Params{Cursor: "page_token", Limit: "page_size"},
+		Limit:  khttp.LimitPolicy{Max: 10, Clamp: true},
+	}
+	r := httptest.NewRequest(http.MethodGet, "/posts?page_token="+keyset.NextToken(keyset.EncodeInt64Cursor(7), 0)+"&page_size=500", nil)
+	p, err := binder.Bind(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Limit != 10 {
+		t.Fatalf("limit must be clamped to 10, got %d", p.Limit)
+	}
+	p.EnsureDefaults()
+	if p.Dir != keyset.DirNext || p.Cursor != keyset.EncodeInt64Cursor(7) {
+		t.Fatalf("token must unwrap to its cursor and direction: %+v", p)
+	}
+
+	// Without a limit parameter, the token's limit and the EnsureDefaults
+	// default are clamped too.
+	for _, token := range []string{keyset.NextToken(keyset.EncodeInt64Cursor(7), 1<<31), keyset.NextToken(keyset.EncodeInt64Cursor(7), 0)} {
+		p, err := binder.Bind(httptest.NewRequest(http.MethodGet, "/posts?page_token="+token, nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p.EnsureDefaults()
+		if p.Limit != 10 {
+			t.Fatalf("limit must be clamped to 10, got %d", p.Limit)
+		}
+	}
+	p, err = khttp.Binder{Limit: khttp.LimitPolicy{Max: 10}}.BindValues(url.Values{"cursor": {keyset.NextToken(keyset.EncodeInt64Cursor(7), 5)}})
+	if err != nil || p.Limit != 0 {
+		t.Fatalf("a token limit within max must be kept: %+v, %v", p, err)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	_, err := khttp.Bind(httptest.NewRequest(http.MethodGet, "/?limit=-1", nil))
+	rec := httptest.NewRecorder()
+	khttp.WriteError(rec, err)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", rec.Code)
+	}
+	var body struct {
+		Error struct {
+			Code, Param, Value, Message string
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.Error.Code != "invalid_parameter" || body.Error.Param != "limit" || body.Error.Value != "-1" {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	khttp.WriteError(rec, errors.New("db is down"))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("want 500, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,115 @@
+package khttp
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Links are the URLs of the pages around a result; "" when a page does not exist.
+type Links struct {
+	Next  string
+	Prev  string
+	First string
+	Last  string
+}
+
+// NewLinks returns the links for res, the page served at u, with the
+// parameter names of b. Every other query parameter of u, such as limit and
+// filters, is kept.
+//
+// Next and Prev carry the result's tokens (see keyset.Result.NextToken) in
+// the cursor parameter together with an explicit dir. First is set when a
+// previous page exists and Last when a next page exists; both carry a
+// snapshot-only token when res has a snapshot, so every link stays in the
+// pinned view.
+func NewLinks[T any](b Binder, u *url.URL, res keyset.Result[T]) Links {
+	names := b.Params.withDefaults()
+	link := func(cursor string, dir keyset.Dir) string {
+		v := u.Query()
+		v.Del(names.After)
+		v.Del(names.Before)
+		v.Del(names.Cursor)
+		if cursor != "" {
+			v.Set(names.Cursor, cursor)
+		}
+		v.Set(names.Dir, DirName(dir))
+		l := *u
+		l.RawQuery = v.Encode()
+		return l.String()
+	}
+
+	end := func(dir keyset.Dir) string {
+		return link(keyset.Token{Dir: dir, Snapshot: res.Snapshot}.Encode(), dir)
+	}
+
+	var l Links
+	if res.HasNext {
+		l.Next = link(res.NextToken(0), keyset.DirNext)
+		l.Last = end(keyset.DirLast)
+	}
+	if res.HasPrev {
+		l.Prev = link(res.PrevToken(0), keyset.DirPrev)
+		l.First = end(keyset.DirFirst)
+	}
+	return l
+}
+
+// String formats l as an RFC 8288 Link header value, e.g.
+//
+//	<https://api.example.com/posts?cursor=...&dir=next>; rel="next", <...>; rel="first"
+func (l Links) String() string {
+	var parts []string
+	for _, r := range []struct{ rel, url string }{
+		{"next", l.Next},
+		{"prev", l.Prev},
+		{"first", l.First},
+		{"last", l.Last},
+	} {
+		if r.url != "" {
+			parts = append(parts, "<"+r.url+`>; rel="`+r.rel+`"`)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// HeaderOptions selects the optional headers written by SetHeaders.
+type HeaderOptions struct {
+	NextCursor bool // X-Next-Cursor: the next page token, omitted on the last page
+	HasMore    bool // X-Has-More: "true" or "false"
+}
+
+// SetHeaders sets the Link header for res (see NewLinks), plus the optional
+// X-Next-Cursor and X-Has-More headers selected by opts.
+// No Link header is set when there are no other pages.
+func SetHeaders[T any](h http.Header, b Binder, u *url.URL, res keyset.Result[T], opts HeaderOptions) {
+	if v := NewLinks(b, u, res).String(); v != "" {
+		h.Set("Link", v)
+	}
+	if opts.NextCursor {
+		if next := res.NextToken(0); next != "" {
+			h.Set("X-Next-Cursor", next)
+		}
+	}
+	if opts.HasMore {
+		h.Set("X-Has-More", strconv.FormatBool(res.HasNext))
+	}
+}
+
+// RequestURL returns the absolute URL of r for use in links. The scheme is
+// https for TLS connections and http otherwise; behind a proxy, build the
+// URL from the public origin instead.
+func RequestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return &u
+}
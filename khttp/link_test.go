@@ -0,0 +1,122 @@
+package khttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestNewLinks(t *testing.T) {
+	t.Parallel()
+
+	res := keyset.Result[int64]{
+		Items:      []int64{4, 5, 6},
+		PrevCursor: keyset.EncodeInt64Cursor(4),
+		NextCursor: keyset.EncodeInt64Cursor(6),
+		HasPrev:    true,
+		HasNext:    true,
+	}
+	u, _ := url.Parse("https://api.example.com/posts?after=abc&limit=3&tag=go")
+	l := khttp.NewLinks(khttp.Binder{}, u, res)
+
+	next, _ := url.Parse(l.Next)
+	q := next.Query()
+	if q.Get("cursor") != res.NextToken(0) || q.Get("dir") != "next" || q.Get("limit") != "3" || q.Get("tag") != "go" || q.Has("after") {
+		t.Fatalf("unexpected next link: %s", l.Next)
+	}
+	if next.Host != "api.example.com" || next.Path != "/posts" {
+		t.Fatalf("next link must keep the origin and path: %s", l.Next)
+	}
+	prev, _ := url.Parse(l.Prev)
+	if prev.Query().Get("cursor") != res.PrevToken(0) || prev.Query().Get("dir") != "prev" {
+		t.Fatalf("unexpected prev link: %s", l.Prev)
+	}
+	first, _ := url.Parse(l.First)
+	if first.Query().Has("cursor") || first.Query().Get("dir") != "first" || first.Query().Get("limit") != "3" {
+		t.Fatalf("unexpected first link: %s", l.First)
+	}
+	if !strings.Contains(l.Last, "dir=last") {
+		t.Fatalf("unexpected last link: %s", l.Last)
+	}
+
+	// Links must bind back to the pages they advertise.
+	p, err := khttp.Binder{}.BindValues(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.EnsureDefaults()
+	if p.Dir != keyset.DirNext || p.Cursor != res.NextCursor || p.Limit != 3 {
+		t.Fatalf("next link binds to %+v", p)
+	}
+}
+
+func TestNewLinks_KeepSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snap := keyset.EncodeInt64Cursor(99)
+	res := keyset.Result[int64]{
+		Items:      []int64{4, 5},
+		PrevCursor: keyset.EncodeInt64Cursor(4),
+		NextCursor: keyset.EncodeInt64Cursor(5),
+		HasPrev:    true,
+		HasNext:    true,
+		Snapshot:   snap,
+	}
+	u, _ := url.Parse("/posts?limit=2")
+	l := khttp.NewLinks(khttp.Binder{}, u, res)
+	for _, c := range []struct {
+		link string
+		dir  keyset.Dir
+	}{
+		{l.Next, keyset.DirNext},
+		{l.Prev, keyset.DirPrev},
+		{l.First, keyset.DirFirst},
+		{l.Last, keyset.DirLast},
+	} {
+		link, _ := url.Parse(c.link)
+		p, err := khttp.Binder{}.BindValues(link.Query())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.link, err)
+		}
+		p.EnsureDefaults()
+		if p.Dir != c.dir || p.Snapshot != snap {
+			t.Fatalf("%s: must stay in the snapshot, binds to %+v", c.link, p)
+		}
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/posts?limit=2", nil)
+	res := keyset.Result[int64]{
+		Items:      []int64{1, 2},
+		PrevCursor: keyset.EncodeInt64Cursor(1),
+		NextCursor: keyset.EncodeInt64Cursor(2),
+		HasNext:    true,
+	}
+	h := http.Header{}
+	khttp.SetHeaders(h, khttp.Binder{}, khttp.RequestURL(r), res, khttp.HeaderOptions{NextCursor: true, HasMore: true})
+
+	link := h.Get("Link")
+	if !strings.HasPrefix(link, "<http://example.com/posts?") || !strings.Contains(link, `rel="next"`) || !strings.Contains(link, `rel="last"`) {
+		t.Fatalf("unexpected Link header: %s", link)
+	}
+	if strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="first"`) {
+		t.Fatalf("first page must not link backwards: %s", link)
+	}
+	if h.Get("X-Next-Cursor") != res.NextToken(0) || h.Get("X-Has-More") != "true" {
+		t.Fatalf("unexpected headers: %v", h)
+	}
+
+	h = http.Header{}
+	khttp.SetHeaders(h, khttp.Binder{}, r.URL, keyset.Result[int64]{}, khttp.HeaderOptions{NextCursor: true, HasMore: true})
+	if h.Get("Link") != "" || h.Get("X-Next-Cursor") != "" || h.Get("X-Has-More") != "false" {
+		t.Fatalf("unexpected headers for a single page: %v", h)
+	}
+}
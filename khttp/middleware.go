@@ -0,0 +1,26 @@
+package khttp
+
+import (
+	"net/http"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Middleware binds the page of every request with b and stores it in the
+// request context, where handlers read it with keyset.PageFromContext.
+// Requests with invalid parameters are answered with a 400 (see WriteError)
+// and do not reach next.
+//
+//	mux.Handle("GET /posts", khttp.Middleware(binder)(http.HandlerFunc(listPosts)))
+func Middleware(b Binder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := b.Bind(r)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(keyset.NewContext(r.Context(), p)))
+		})
+	}
+}
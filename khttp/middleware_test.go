@@ -0,0 +1,39 @@
+package khttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got keyset.Page
+	h := khttp.Middleware(khttp.Binder{Limit: khttp.LimitPolicy{Default: 10, Max: 50}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := keyset.PageFromContext(r.Context())
+		if !ok {
+			t.Error("page must be in the context")
+		}
+		got = p
+	}))
+
+	cur := keyset.EncodeInt64Cursor(5)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?before="+cur, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d", rec.Code)
+	}
+	if want := (keyset.Page{Cursor: cur, Dir: keyset.DirPrev, Limit: 10}); got != want {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?limit=51", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,184 @@
+package khttp
+
+import "fmt"
+
+// Schema is an OpenAPI 3.1 (JSON Schema 2020-12) object, ready to be
+// marshaled to JSON or YAML and merged into a generated spec.
+type Schema = map[string]any
+
+// Component names used by Components, under #/components/parameters and
+// #/components/schemas.
+const (
+	ParamCursor = "PageCursor"
+	ParamLimit  = "PageLimit"
+	ParamDir    = "PageDir"
+	ParamAfter  = "PageAfter"
+	ParamBefore = "PageBefore"
+	ParamSort   = "PageSort"
+	SchemaInfo  = "PageInfo"
+	SchemaLinks = "PageLinks"
+	SchemaError = "PageError"
+)
+
+const componentRef = "#/components/"
+
+// Components is a fragment of an OpenAPI 3.1 components object.
+type Components struct {
+	Parameters map[string]Schema `json:"parameters"`
+	Schemas    map[string]Schema `json:"schemas"`
+}
+
+// Components describes the pagination parameters accepted by b and the
+// schemas of envelopes in style, so the documentation follows the runtime
+// configuration: parameter names, the limit policy and the sortable fields.
+// The sort parameter is only included when SortPolicy.Fields is set.
+//
+// Reference the parameters from operations with ParameterRefs and the
+// response body with EnvelopeSchema.
+func (b Binder) Components(style Style) Components {
+	names := b.Params.withDefaults()
+	cursor := func(name, desc string) Schema {
+		return Schema{"name": name, "in": "query", "description": desc, "schema": Schema{"type": "string"}}
+	}
+
+	limit := Schema{"type": "integer", "minimum": 1}
+	if b.Limit.Default > 0 {
+		limit["default"] = b.Limit.Default
+	}
+	limitDesc := "Number of items per page."
+	switch {
+	case b.Limit.Max > 0 && b.Limit.Clamp:
+		limitDesc += fmt.Sprintf(" Values above %d are lowered to %d.", b.Limit.Max, b.Limit.Max)
+	case b.Limit.Max > 0:
+		limit["maximum"] = b.Limit.Max
+	}
+
+	c := Components{
+		Parameters: map[string]Schema{
+			ParamCursor: cursor(names.Cursor, "Opaque cursor or page token from a previous response."),
+			ParamAfter:  cursor(names.After, "Cursor to continue after; same as "+names.Cursor+" with "+names.Dir+"=next."),
+			ParamBefore: cursor(names.Before, "Cursor to continue before; same as "+names.Cursor+" with "+names.Dir+"=prev."),
+			ParamLimit:  {"name": names.Limit, "in": "query", "description": limitDesc, "schema": limit},
+			ParamDir: {"name": names.Dir, "in": "query", "description": "Direction of the page relative to the cursor.",
+				"schema": Schema{"type": "string", "enum": []string{"next", "prev", "first", "last"}}},
+		},
+		Schemas: map[string]Schema{
+			SchemaInfo:  pageInfoSchema(style),
+			SchemaLinks: pageLinksSchema(),
+			SchemaError: errorSchema(),
+		},
+	}
+	if len(b.Sort.Fields) > 0 {
+		enum := make([]string, 0, 2*len(b.Sort.Fields))
+		for _, f := range b.Sort.Fields {
+			enum = append(enum, f, "-"+f)
+		}
+		sort := Schema{"type": "string", "enum": enum}
+		if b.Sort.Default != "" {
+			sort["default"] = b.Sort.Default
+		}
+		c.Parameters[ParamSort] = Schema{"name": names.Sort, "in": "query", "schema": sort,
+			"description": "Field to sort by; prefix with - for descending order. Changing it invalidates cursors."}
+	}
+	return c
+}
+
+// ParameterRefs returns $ref objects for the parameters of Components,
+// for the parameters list of a paginated operation.
+func (b Binder) ParameterRefs() []Schema {
+	names := []string{ParamCursor, ParamAfter, ParamBefore, ParamLimit, ParamDir}
+	if len(b.Sort.Fields) > 0 {
+		names = append(names, ParamSort)
+	}
+	refs := make([]Schema, len(names))
+	for i, n := range names {
+		refs[i] = Schema{"$ref": componentRef + "parameters/" + n}
+	}
+	return refs
+}
+
+// EnvelopeSchema returns the schema of an Envelope in style whose items are
+// described by itemRef, e.g. "#/components/schemas/Post". It references the
+// PageInfo and PageLinks schemas of Components.
+func EnvelopeSchema(style Style, itemRef string) Schema {
+	data := Schema{"type": "array", "items": Schema{"$ref": itemRef}}
+	info := Schema{"$ref": componentRef + "schemas/" + SchemaInfo}
+	if style == StyleJSONAPI {
+		return Schema{
+			"type":     "object",
+			"required": []string{"data", "meta"},
+			"properties": Schema{
+				"data":  data,
+				"links": Schema{"$ref": componentRef + "schemas/" + SchemaLinks},
+				"meta":  info,
+			},
+		}
+	}
+	return Schema{
+		"allOf": []Schema{
+			info,
+			{"type": "object", "required": []string{"data"}, "properties": Schema{"data": data}},
+		},
+	}
+}
+
+// pageInfoSchema describes the pagination fields of an Envelope in style,
+// matching its JSON names.
+func pageInfoSchema(style Style) Schema {
+	n := map[string]string{
+		"next": "next_cursor", "prev": "prev_cursor", "more": "has_more",
+		"hasPrev": "has_prev", "total": "total", "capped": "total_capped",
+		"estimated": "total_estimated",
+	}
+	if style != StyleSnake {
+		n = map[string]string{
+			"next": "nextCursor", "prev": "prevCursor", "more": "hasMore",
+			"hasPrev": "hasPrev", "total": "total", "capped": "totalCapped",
+			"estimated": "totalEstimated",
+		}
+	}
+	str := func(desc string) Schema { return Schema{"type": "string", "description": desc} }
+	boolean := func(desc string) Schema { return Schema{"type": "boolean", "description": desc} }
+	return Schema{
+		"type":     "object",
+		"required": []string{n["more"], n["hasPrev"]},
+		"properties": Schema{
+			n["next"]:      str("Token for the next page; absent on the last page."),
+			n["prev"]:      str("Token for the previous page; absent on the first page."),
+			n["more"]:      boolean("Whether a next page exists."),
+			n["hasPrev"]:   boolean("Whether a previous page exists."),
+			n["total"]:     Schema{"type": "integer", "description": "Total number of items, if requested."},
+			n["capped"]:    boolean("Whether total is a lower bound."),
+			n["estimated"]: boolean("Whether total is a planner estimate rather than an exact count."),
+		},
+	}
+}
+
+// pageLinksSchema describes the links object of a JSON:API envelope.
+func pageLinksSchema() Schema {
+	link := Schema{"type": "string", "format": "uri-reference"}
+	return Schema{
+		"type":       "object",
+		"properties": Schema{"next": link, "prev": link, "first": link, "last": link},
+	}
+}
+
+// errorSchema describes the body written by WriteError.
+func errorSchema() Schema {
+	return Schema{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": Schema{
+			"error": Schema{
+				"type":     "object",
+				"required": []string{"code", "message"},
+				"properties": Schema{
+					"code":    Schema{"type": "string", "enum": []string{"invalid_parameter", "internal"}},
+					"param":   Schema{"type": "string"},
+					"value":   Schema{"type": "string"},
+					"message": Schema{"type": "string"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package khttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/khttp"
+)
+
+func TestBinder_BindSort(t *testing.T) {
+	t.Parallel()
+
+	b := khttp.Binder{Sort: khttp.SortPolicy{Fields: []string{"created_at", "title"}, Default: "-created_at"}}
+	cases := []struct {
+		query string
+		want  khttp.Sort
+	}{
+		{"", khttp.Sort{Field: "created_at", Order: keyset.Descending}},
+		{"sort=title", khttp.Sort{Field: "title", Order: keyset.Ascending}},
+		{"sort=-title", khttp.Sort{Field: "title", Order: keyset.Descending}},
+	}
+	for _, c := range cases {
+		q, _ := url.ParseQuery(c.query)
+		got, err := b.BindSortValues(q)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", c.query, err)
+		}
+		if got != c.want {
+			t.Fatalf("%q: want %+v, got %+v", c.query, c.want, got)
+		}
+	}
+	for _, query := range []string{"sort=password", "sort=--title", "sort=title&sort=-title"} {
+		q, _ := url.ParseQuery(query)
+		if _, err := b.BindSortValues(q); err == nil {
+			t.Fatalf("%q: want an error", query)
+		}
+		// Bind, and with it Middleware, rejects the same sorts.
+		var e *khttp.Error
+		if _, err := b.BindValues(q); !errors.As(err, &e) || e.Param != "sort" {
+			t.Fatalf("%q: want a sort error from BindValues, got %v", query, err)
+		}
+	}
+}
+
+func TestBinder_Components(t *testing.T) {
+	t.Parallel()
+
+	b := khttp.Binder{
+		Params: khttp.Params{Limit: "per_page"},
+		Limit:  khttp.LimitPolicy{Default: 20, Max: 100},
+		Sort:   khttp.SortPolicy{Fields: []string{"created_at", "id"}},
+	}
+	c := b.Components(khttp.StyleSnake)
+
+	limit := c.Parameters[khttp.ParamLimit]
+	if limit["name"] != "per_page" {
+		t.Fatalf("limit parameter must use the configured name: %v", limit)
+	}
+	schema := limit["schema"].(khttp.Schema)
+	if schema["maximum"] != 100 || schema["default"] != 20 || schema["minimum"] != 1 {
+		t.Fatalf("limit schema must follow the limit policy: %v", schema)
+	}
+
+	// Every documented sort value must be accepted at runtime, and the
+	// documented limits must match the binder's validation.
+	for _, v := range c.Parameters[khttp.ParamSort]["schema"].(khttp.Schema)["enum"].([]string) {
+		if _, err := b.BindSortValues(url.Values{"sort": {v}}); err != nil {
+			t.Fatalf("documented sort %q rejected: %v", v, err)
+		}
+	}
+	for n, ok := range map[int]bool{1: true, 100: true, 0: false, 101: false} {
+		_, err := b.BindValues(url.Values{"per_page": {strconv.Itoa(n)}})
+		if (err == nil) != ok {
+			t.Fatalf("limit %d: documented valid=%v, got error %v", n, ok, err)
+		}
+	}
+
+	if refs := b.ParameterRefs(); len(refs) != len(c.Parameters) {
+		t.Fatalf("want a ref per parameter, got %v", refs)
+	}
+	for _, ref := range b.ParameterRefs() {
+		name := strings.TrimPrefix(ref["$ref"].(string), "#/components/parameters/")
+		if _, ok := c.Parameters[name]; !ok {
+			t.Fatalf("dangling ref %v", ref)
+		}
+	}
+	if _, ok := (khttp.Binder{}).Components(khttp.StyleSnake).Parameters[khttp.ParamSort]; ok {
+		t.Fatal("sort must not be documented without sortable fields")
+	}
+}
+
+func TestEnvelopeSchema_MatchesEnvelope(t *testing.T) {
+	t.Parallel()
+
+	for _, style := range []khttp.Style{khttp.StyleSnake, khttp.StyleCamel, khttp.StyleJSONAPI} {
+		info := (khttp.Binder{}).Components(style).Schemas[khttp.SchemaInfo]
+		props := info["properties"].(khttp.Schema)
+
+		total := int64(3)
+		b, _ := json.Marshal(khttp.Envelope[item]{NextCursor: "n", PrevCursor: "p", Total: &total, TotalCapped: true, TotalEstimated: true, Style: style})
+		var body map[string]json.RawMessage
+		_ = json.Unmarshal(b, &body)
+		fields := body
+		if style == khttp.StyleJSONAPI {
+			fields = nil
+			_ = json.Unmarshal(body["meta"], &fields)
+			if _, ok := khttp.EnvelopeSchema(style, "#/components/schemas/Item")["properties"].(khttp.Schema)["meta"]; !ok {
+				t.Fatal("JSON:API envelope schema must have meta")
+			}
+		}
+		for k := range fields {
+			if k == "data" {
+				continue
+			}
+			if _, ok := props[k]; !ok {
+				t.Fatalf("style %d: field %q is not documented in %v", style, k, props)
+			}
+		}
+	}
+}
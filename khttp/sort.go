@@ -0,0 +1,66 @@
+package khttp
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// SortPolicy is the allowlist of fields clients may sort by. The sort
+// parameter takes a field name for ascending order, or the name prefixed
+// with "-" for descending order, e.g. sort=-created_at.
+type SortPolicy struct {
+	Fields  []string // Sortable fields; empty disables the sort parameter
+	Default string   // Sort used when none is given, e.g. "-created_at"
+}
+
+// Sort is a validated sort field and order.
+type Sort struct {
+	Field string
+	Order keyset.Order
+}
+
+// ParseSort parses a sort value such as "created_at" or "-created_at".
+func ParseSort(s string) Sort {
+	if f, ok := strings.CutPrefix(s, "-"); ok {
+		return Sort{Field: f, Order: keyset.Descending}
+	}
+	return Sort{Field: s, Order: keyset.Ascending}
+}
+
+// BindSort reads the sort of r (see BindSortValues).
+func (b Binder) BindSort(r *http.Request) (Sort, error) {
+	return b.BindSortValues(r.URL.Query())
+}
+
+// BindSortValues reads the sort parameter from q and checks it against the
+// sort policy. Without a sort parameter it returns SortPolicy.Default, or
+// the zero Sort if that is unset. Errors are of type *Error.
+//
+// Cursors are only valid for the sort they were issued under; clients that
+// change the sort must start over from the first page.
+func (b Binder) BindSortValues(q url.Values) (Sort, error) {
+	name := b.Params.withDefaults().Sort
+	v, ok := single(q, name)
+	if !ok {
+		return Sort{}, &Error{Param: name, Message: "must be given at most once"}
+	}
+	if v == "" {
+		if b.Sort.Default == "" {
+			return Sort{}, nil
+		}
+		return ParseSort(b.Sort.Default), nil
+	}
+	s := ParseSort(v)
+	if !slices.Contains(b.Sort.Fields, s.Field) {
+		msg := "sorting is not supported"
+		if len(b.Sort.Fields) > 0 {
+			msg = "must be one of " + strings.Join(b.Sort.Fields, ", ") + ", optionally prefixed with -"
+		}
+		return Sort{}, &Error{Param: name, Value: v, Message: msg}
+	}
+	return s, nil
+}
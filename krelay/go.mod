@@ -0,0 +1,7 @@
+module github.com/mickamy/go-keyset/krelay
+
+go 1.23.0
+
+replace github.com/mickamy/go-keyset => ..
+
+require github.com/mickamy/go-keyset v0.0.0
@@ -0,0 +1,205 @@
+// Package krelay implements the GraphQL Relay Cursor Connections
+// specification on top of keyset pagination.
+//
+// Args maps the first/after/last/before arguments onto a keyset.Page, and
+// Connection carries edges with per-node cursors and pageInfo. The types use
+// the field names of the specification, so gqlgen can bind them directly.
+//
+// Example (gqlgen resolver):
+//
+//	type PostConnection = krelay.Connection[*model.Post]
+//
+//	func (r *queryResolver) Posts(ctx context.Context, first *int, after *string, last *int, before *string) (*PostConnection, error) {
+//		args := krelay.Args{First: first, After: after, Last: last, Before: before}
+//		return krelay.Resolve(ctx, args, krelay.Options{DefaultLimit: 20, MaxLimit: 100}, fetchPosts, postCursor)
+//	}
+package krelay
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Args are the connection arguments of a field, as generated by gqlgen
+// for nullable Int and String arguments.
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// Options configures how Args map onto a Page.
+type Options struct {
+	// DefaultLimit is used when neither first nor last is given.
+	// If 0, one of them is required.
+	DefaultLimit int
+
+	// MaxLimit is the largest accepted first or last; 0 means no upper bound.
+	MaxLimit int
+
+	// CheckCursor optionally validates the after and before cursors,
+	// e.g. by decoding them with keyset.DecodeTimeAndInt64Cursor.
+	CheckCursor func(cursor string) error
+}
+
+// Error reports invalid connection arguments.
+type Error struct {
+	Arg     string // Name of the offending argument
+	Message string // Human-readable reason
+	Err     error  // Underlying error, if any
+}
+
+func (e *Error) Error() string {
+	return "krelay: invalid argument " + strconv.Quote(e.Arg) + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Page returns the keyset.Page selected by a:
+//
+//   - first reads forward from after (DirNext), or from the start (DirFirst)
+//   - last reads backward from before (DirPrev), or from the end (DirLast)
+//   - the other cursor, if any, bounds the window (see keyset.Page.Until)
+//
+// Following the specification, a negative first or last is an error. Giving
+// both first and last, which the specification strongly discourages, is
+// rejected too. A first or last of 0 yields a Page with Limit 0, which
+// Resolve answers with an empty connection without fetching.
+//
+// All errors are of type *Error.
+func (a Args) Page(opts Options) (keyset.Page, error) {
+	after, before := deref(a.After), deref(a.Before)
+	if opts.CheckCursor != nil {
+		for _, c := range []struct{ arg, cursor string }{{"after", after}, {"before", before}} {
+			if c.cursor == "" {
+				continue
+			}
+			if err := opts.CheckCursor(c.cursor); err != nil {
+				return keyset.Page{}, &Error{Arg: c.arg, Message: "malformed cursor", Err: err}
+			}
+		}
+	}
+
+	if a.First != nil && a.Last != nil {
+		return keyset.Page{}, &Error{Arg: "last", Message: "cannot be combined with first"}
+	}
+	limit, arg := opts.DefaultLimit, "first"
+	switch {
+	case a.First != nil:
+		limit = *a.First
+	case a.Last != nil:
+		limit, arg = *a.Last, "last"
+	case opts.DefaultLimit <= 0:
+		return keyset.Page{}, &Error{Arg: "first", Message: "first or last is required"}
+	}
+	if limit < 0 {
+		return keyset.Page{}, &Error{Arg: arg, Message: "must not be negative"}
+	}
+	if opts.MaxLimit > 0 && limit > opts.MaxLimit {
+		return keyset.Page{}, &Error{Arg: arg, Message: "must not exceed " + strconv.Itoa(opts.MaxLimit)}
+	}
+
+	if a.Last != nil {
+		p := keyset.Page{Cursor: before, Dir: keyset.DirPrev, Until: after, Limit: limit}
+		if before == "" {
+			p.Dir = keyset.DirLast
+		}
+		return p, nil
+	}
+	p := keyset.Page{Cursor: after, Dir: keyset.DirNext, Until: before, Limit: limit}
+	if after == "" {
+		p.Dir = keyset.DirFirst
+	}
+	return p, nil
+}
+
+// Edge is an item of a connection with its cursor.
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// PageInfo is the pageInfo object of a connection.
+// StartCursor and EndCursor are nil for an empty connection.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Connection is a page of nodes in the shape of the specification.
+// TotalCount is set when the result carries a total (see keyset.PaginateWithTotal);
+// TotalCountCapped and TotalCountEstimated mark lower bounds and planner estimates.
+type Connection[T any] struct {
+	Edges               []Edge[T] `json:"edges"`
+	PageInfo            PageInfo  `json:"pageInfo"`
+	TotalCount          *int64    `json:"totalCount,omitempty"`
+	TotalCountCapped    bool      `json:"totalCountCapped,omitempty"`
+	TotalCountEstimated bool      `json:"totalCountEstimated,omitempty"`
+}
+
+// Nodes returns the nodes of the edges, for schemas exposing a nodes shortcut.
+func (c *Connection[T]) Nodes() []T {
+	nodes := make([]T, len(c.Edges))
+	for i, e := range c.Edges {
+		nodes[i] = e.Node
+	}
+	return nodes
+}
+
+// NewConnection returns the connection for res, with each edge's cursor
+// produced by cursor, the keyset cursor of the sort key.
+func NewConnection[T any](res keyset.Result[T], cursor keyset.CursorFunc[T]) *Connection[T] {
+	c := &Connection[T]{
+		Edges: make([]Edge[T], len(res.Items)),
+		PageInfo: PageInfo{
+			HasNextPage:     res.HasNext,
+			HasPreviousPage: res.HasPrev,
+		},
+	}
+	for i, item := range res.Items {
+		c.Edges[i] = Edge[T]{Node: item, Cursor: cursor(item)}
+	}
+	if n := len(c.Edges); n > 0 {
+		c.PageInfo.StartCursor = &c.Edges[0].Cursor
+		c.PageInfo.EndCursor = &c.Edges[n-1].Cursor
+	}
+	if res.Total != nil {
+		n := res.Total.Count
+		c.TotalCount = &n
+		c.TotalCountCapped = res.Total.Capped
+		c.TotalCountEstimated = res.Total.Mode == keyset.CountEstimate
+	}
+	return c
+}
+
+// Resolve maps a onto a Page, fetches it with keyset.Paginate and returns
+// the connection. A first or last of 0 returns an empty connection without
+// calling fetch.
+func Resolve[T any](ctx context.Context, a Args, opts Options, fetch keyset.FetchFunc[T], cursor keyset.CursorFunc[T]) (*Connection[T], error) {
+	p, err := a.Page(opts)
+	if err != nil {
+		return nil, err
+	}
+	if p.Limit == 0 {
+		return NewConnection(keyset.Result[T]{}, cursor), nil
+	}
+	res, err := keyset.Paginate(ctx, p, fetch, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return NewConnection(res, cursor), nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
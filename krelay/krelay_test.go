@@ -0,0 +1,168 @@
+package krelay_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/krelay"
+)
+
+// memFetch serves ids (ascending) honoring Cursor, Until and Dir.
+func memFetch(ids []int64, calls *int) keyset.FetchFunc[int64] {
+	return func(_ context.Context, p keyset.Page) ([]int64, error) {
+		*calls++
+		lo, hi := int64(-1<<63), int64(1<<63-1)
+		if c, err := keyset.DecodeInt64Cursor(p.Cursor); err == nil {
+			if p.Dir.Backward() {
+				hi = c - 1
+			} else {
+				lo = c + 1
+			}
+		}
+		if u, err := keyset.DecodeInt64Cursor(p.Until); err == nil {
+			if p.Dir.Backward() {
+				lo = u + 1
+			} else {
+				hi = u - 1
+			}
+		}
+		var window []int64
+		for _, id := range ids {
+			if id >= lo && id <= hi {
+				window = append(window, id)
+			}
+		}
+		if len(window) > p.Limit {
+			if p.Dir.Backward() {
+				window = window[len(window)-p.Limit:]
+			} else {
+				window = window[:p.Limit]
+			}
+		}
+		return window, nil
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8}
+	cur := keyset.EncodeInt64Cursor
+	cases := []struct {
+		name             string
+		args             krelay.Args
+		want             []int64
+		hasPrev, hasNext bool
+	}{
+		{"first", krelay.Args{First: ptr(3)}, []int64{1, 2, 3}, false, true},
+		{"first after", krelay.Args{First: ptr(3), After: ptr(cur(3))}, []int64{4, 5, 6}, true, true},
+		{"first after to end", krelay.Args{First: ptr(5), After: ptr(cur(6))}, []int64{7, 8}, true, false},
+		{"last", krelay.Args{Last: ptr(2)}, []int64{7, 8}, true, false},
+		{"last before", krelay.Args{Last: ptr(2), Before: ptr(cur(4))}, []int64{2, 3}, true, true},
+		{"first after before", krelay.Args{First: ptr(10), After: ptr(cur(2)), Before: ptr(cur(6))}, []int64{3, 4, 5}, true, false},
+		{"last after before", krelay.Args{Last: ptr(2), After: ptr(cur(2)), Before: ptr(cur(6))}, []int64{4, 5}, true, true},
+		{"first before", krelay.Args{First: ptr(2), Before: ptr(cur(6))}, []int64{1, 2}, false, true},
+		{"default limit", krelay.Args{}, []int64{1, 2, 3, 4}, false, true},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			conn, err := krelay.Resolve(context.Background(), c.args, krelay.Options{DefaultLimit: 4}, memFetch(ids, &calls), cur)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := conn.Nodes(); !slices.Equal(got, c.want) {
+				t.Fatalf("nodes want %v, got %v", c.want, got)
+			}
+			for _, e := range conn.Edges {
+				if e.Cursor != cur(e.Node) {
+					t.Fatalf("edge %d has cursor %q", e.Node, e.Cursor)
+				}
+			}
+			pi := conn.PageInfo
+			if pi.HasPreviousPage != c.hasPrev || pi.HasNextPage != c.hasNext {
+				t.Fatalf("pageInfo want prev=%v next=%v, got %+v", c.hasPrev, c.hasNext, pi)
+			}
+			if *pi.StartCursor != cur(c.want[0]) || *pi.EndCursor != cur(c.want[len(c.want)-1]) {
+				t.Fatalf("start/end cursors mismatch: %+v", pi)
+			}
+		})
+	}
+}
+
+func TestResolve_Zero(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	conn, err := krelay.Resolve(context.Background(), krelay.Args{First: ptr(0)}, krelay.Options{}, memFetch([]int64{1, 2}, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 || len(conn.Edges) != 0 || conn.PageInfo.StartCursor != nil || conn.PageInfo.EndCursor != nil {
+		t.Fatalf("first: 0 must return an empty connection without fetching: %+v (calls %d)", conn, calls)
+	}
+}
+
+func TestArgs_PageErrors(t *testing.T) {
+	t.Parallel()
+
+	opts := krelay.Options{
+		MaxLimit: 50,
+		CheckCursor: func(s string) error {
+			_, err := keyset.DecodeInt64Cursor(s)
+			return err
+		},
+	}
+	cases := []struct {
+		name string
+		args krelay.Args
+		arg  string
+	}{
+		{"no limit", krelay.Args{}, "first"},
+		{"negative first", krelay.Args{First: ptr(-1)}, "first"},
+		{"negative last", krelay.Args{Last: ptr(-1)}, "last"},
+		{"first and last", krelay.Args{First: ptr(1), Last: ptr(1)}, "last"},
+		{"above max", krelay.Args{Last: ptr(51)}, "last"},
+		{"malformed after", krelay.Args{First: ptr(1), After: ptr("???")}, "after"},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := c.args.Page(opts)
+			var e *krelay.Error
+			if !errors.As(err, &e) || e.Arg != c.arg {
+				t.Fatalf("want *krelay.Error for %q, got %v", c.arg, err)
+			}
+		})
+	}
+}
+
+func TestNewConnection_Total(t *testing.T) {
+	t.Parallel()
+
+	cursor := func(id int64) string { return keyset.EncodeInt64Cursor(id) }
+	cases := []struct {
+		total             keyset.Total
+		capped, estimated bool
+	}{
+		{keyset.Total{Count: 3, Mode: keyset.CountExact}, false, false},
+		{keyset.Total{Count: 1000, Mode: keyset.CountCapped, Capped: true}, true, false},
+		{keyset.Total{Count: 5000, Mode: keyset.CountEstimate}, false, true},
+	}
+	for _, c := range cases {
+		conn := krelay.NewConnection(keyset.Result[int64]{Items: []int64{1}, Total: &c.total}, cursor)
+		if conn.TotalCount == nil || *conn.TotalCount != c.total.Count ||
+			conn.TotalCountCapped != c.capped || conn.TotalCountEstimated != c.estimated {
+			t.Fatalf("total %+v: got count %v, capped %v, estimated %v",
+				c.total, conn.TotalCount, conn.TotalCountCapped, conn.TotalCountEstimated)
+		}
+	}
+}
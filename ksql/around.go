@@ -0,0 +1,18 @@
+package ksql
+
+import (
+	"context"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Around runs the two windows of an "around" page anchored at p.Cursor (see
+// keyset.AroundPages) and merges them in display order:
+//
+//	build := func(p keyset.Page) (string, []any) {
+//		return ksql.QueryByTimeAndID(`SELECT id, body, created_at FROM messages`, p, keyset.Ascending, "created_at", "id", ksql.PlaceholderDollar)
+//	}
+//	res, err := ksql.Around(ctx, db, keyset.Page{Cursor: anchor, Limit: 25}, build, scanMessage, messageCursor)
+func Around[T any](ctx context.Context, db Querier, p keyset.Page, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) (keyset.Result[T], error) {
+	return keyset.Around(ctx, p, Fetch(db, build, scan), cursor)
+}
@@ -0,0 +1,35 @@
+package ksql_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestAround(t *testing.T) {
+	t.Parallel()
+	db := openSQLite(t, 20)
+
+	build := func(p keyset.Page) (string, []any) {
+		return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderQuestion)
+	}
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(10), Limit: 3}
+	res, err := ksql.Around(context.Background(), db, p, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Display order is DESC: three newer items, the anchor, three older items.
+	if want := []int64{13, 12, 11, 10, 9, 8, 7}; !slices.Equal(res.Items, want) {
+		t.Fatalf("items want %v, got %v", want, res.Items)
+	}
+	if !res.HasPrev || !res.HasNext {
+		t.Fatalf("want both ends open, got %+v", res)
+	}
+	if res.PrevCursor != keyset.EncodeInt64Cursor(13) || res.NextCursor != keyset.EncodeInt64Cursor(7) {
+		t.Fatalf("unexpected cursors: %+v", res)
+	}
+}
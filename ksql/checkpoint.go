@@ -0,0 +1,161 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Execer is the subset of *sql.DB, *sql.Tx and *sql.Conn used to run statements.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// DBTX is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type DBTX interface {
+	Querier
+	Execer
+}
+
+// DefaultCheckpointTable is the table name used by NewCheckpointTable when none is given.
+const DefaultCheckpointTable = "keyset_checkpoints"
+
+// CheckpointTable is a keyset.CheckpointStore backed by a SQL table with the
+// columns (name, cursor_value). See CreateTable for the schema.
+//
+// Saving is done with a portable UPDATE, falling back to INSERT when no row
+// exists yet, so it works on PostgreSQL, MySQL and SQLite alike.
+type CheckpointTable struct {
+	db    DBTX
+	table string
+	ph    Placeholder
+}
+
+// NewCheckpointTable returns a CheckpointTable using db. An empty table
+// defaults to DefaultCheckpointTable.
+func NewCheckpointTable(db DBTX, table string, ph Placeholder) *CheckpointTable {
+	if table == "" {
+		table = DefaultCheckpointTable
+	}
+	return &CheckpointTable{db: db, table: table, ph: ph}
+}
+
+// CreateTable creates the checkpoint table if it does not exist yet.
+func (t *CheckpointTable) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (name VARCHAR(255) PRIMARY KEY, cursor_value TEXT NOT NULL)", t.table)
+	if _, err := t.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("ksql: create checkpoint table: %w", err)
+	}
+	return nil
+}
+
+// Load implements keyset.CheckpointStore.
+func (t *CheckpointTable) Load(ctx context.Context, key string) (string, error) {
+	query := fmt.Sprintf("SELECT cursor_value FROM %s WHERE name = %s", t.table, t.ph(1))
+	cursors, err := Query(ctx, t.db, query, []any{key}, func(rows *sql.Rows) (string, error) {
+		var c string
+		err := rows.Scan(&c)
+		return c, err
+	})
+	if err != nil || len(cursors) == 0 {
+		return "", err
+	}
+	return cursors[0], nil
+}
+
+// Save implements keyset.CheckpointStore.
+func (t *CheckpointTable) Save(ctx context.Context, key, cursor string) error {
+	return t.SaveWith(ctx, t.db, key, cursor)
+}
+
+// SaveWith stores cursor for key using db, typically the *sql.Tx that also
+// carries the work done for the page, so both commit or roll back together.
+func (t *CheckpointTable) SaveWith(ctx context.Context, db DBTX, key, cursor string) error {
+	update := fmt.Sprintf("UPDATE %s SET cursor_value = %s WHERE name = %s", t.table, t.ph(1), t.ph(2))
+	res, err := db.ExecContext(ctx, update, cursor, key)
+	if err != nil {
+		return fmt.Errorf("ksql: save checkpoint: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("ksql: save checkpoint: %w", err)
+	} else if n > 0 {
+		return nil
+	}
+
+	// MySQL reports 0 affected rows when the value is unchanged, so make sure
+	// the row is really missing before inserting it.
+	exists := fmt.Sprintf("SELECT 1 FROM %s WHERE name = %s", t.table, t.ph(1))
+	found, err := Query(ctx, db, exists, []any{key}, func(rows *sql.Rows) (int, error) {
+		var one int
+		err := rows.Scan(&one)
+		return one, err
+	})
+	if err != nil {
+		return fmt.Errorf("ksql: save checkpoint: %w", err)
+	}
+	if len(found) > 0 {
+		return nil
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (name, cursor_value) VALUES (%s, %s)", t.table, t.ph(1), t.ph(2))
+	if _, err := db.ExecContext(ctx, insert, key, cursor); err != nil {
+		return fmt.Errorf("ksql: save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// TxWalker is a checkpointed walker whose callback runs inside a transaction
+// that also saves the checkpoint. A page is therefore either fully processed
+// and checkpointed, or not at all: after a crash the scan resumes right after
+// the last committed page (exactly-once for work done through tx).
+type TxWalker[T any] struct {
+	DB     *sql.DB              // Used for reading pages and beginning transactions
+	Store  *CheckpointTable     // Checkpoint table, written through the page transaction
+	Key    string               // Name of the scan within Store
+	Build  BuildFunc            // Builds the page query
+	Scan   ScanFunc[T]          // Scans a row
+	Cursor keyset.CursorFunc[T] // Encodes the cursor of an item
+	Page   keyset.Page          // Initial page (Limit/Dir); Cursor is used only without a checkpoint
+}
+
+// Walk resumes from the stored cursor (if any) and calls fn for every page
+// within its own transaction, committing the checkpoint alongside.
+func (w TxWalker[T]) Walk(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx, batch []T) error) error {
+	stored, err := w.Store.Load(ctx, w.Key)
+	if err != nil {
+		return fmt.Errorf("ksql: load checkpoint %q: %w", w.Key, err)
+	}
+	p := w.Page.Resume(stored)
+	p.EnsureDefaults()
+
+	for batch, err := range Pages(ctx, w.DB, p, w.Build, w.Scan, w.Cursor) {
+		if err != nil {
+			return err
+		}
+		if err := w.commitPage(ctx, w.Cursor(keyset.BoundaryItem(p, batch)), batch, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w TxWalker[T]) commitPage(ctx context.Context, next string, batch []T, fn func(context.Context, *sql.Tx, []T) error) error {
+	tx, err := w.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ksql: begin: %w", err)
+	}
+	if err := fn(ctx, tx, batch); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := w.Store.SaveWith(ctx, tx, w.Key, next); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ksql: commit: %w", err)
+	}
+	return nil
+}
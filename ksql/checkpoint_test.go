@@ -0,0 +1,145 @@
+package ksql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+// openSQLite returns a file-backed SQLite database with a posts table holding ids 1..n.
+func openSQLite(t *testing.T, n int) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "keyset.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT NOT NULL)`); err != nil {
+		t.Fatalf("create posts: %v", err)
+	}
+	for i := 1; i <= n; i++ {
+		if _, err := db.ExecContext(ctx, `INSERT INTO posts (id, title) VALUES (?, ?)`, i, "post"); err != nil {
+			t.Fatalf("insert post: %v", err)
+		}
+	}
+	return db
+}
+
+func TestCheckpointTable_LoadSave(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openSQLite(t, 0)
+
+	store := ksql.NewCheckpointTable(db, "", ksql.PlaceholderQuestion)
+	if err := store.CreateTable(ctx); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	if got, err := store.Load(ctx, "job"); err != nil || got != "" {
+		t.Fatalf("want empty checkpoint, got (%q, %v)", got, err)
+	}
+	for _, c := range []string{"a", "b"} {
+		if err := store.Save(ctx, "job", c); err != nil {
+			t.Fatalf("save %s: %v", c, err)
+		}
+	}
+	if got, err := store.Load(ctx, "job"); err != nil || got != "b" {
+		t.Fatalf("want b, got (%q, %v)", got, err)
+	}
+}
+
+func TestCheckpointTable_SaveUnchangedOnMySQL(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	// MySQL counts only changed rows, so re-saving the same cursor affects none.
+	mock.ExpectExec(`UPDATE keyset_checkpoints SET cursor_value = \? WHERE name = \?`).
+		WithArgs("a", "job").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT 1 FROM keyset_checkpoints WHERE name = \?`).
+		WithArgs("job").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	store := ksql.NewCheckpointTable(db, "", ksql.PlaceholderQuestion)
+	if err := store.Save(context.Background(), "job", "a"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("must not insert an existing checkpoint: %v", err)
+	}
+}
+
+func TestTxWalker_RollsBackWorkAndCheckpointTogether(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openSQLite(t, 5)
+	if _, err := db.ExecContext(ctx, `CREATE TABLE processed (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatalf("create processed: %v", err)
+	}
+
+	store := ksql.NewCheckpointTable(db, "", ksql.PlaceholderQuestion)
+	if err := store.CreateTable(ctx); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	w := ksql.TxWalker[int64]{
+		DB:    db,
+		Store: store,
+		Key:   "copy-posts",
+		Build: func(p keyset.Page) (string, []any) {
+			return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderQuestion)
+		},
+		Scan:   scanID,
+		Cursor: keyset.EncodeInt64Cursor,
+		Page:   keyset.Page{Limit: 2},
+	}
+
+	boom := errors.New("boom")
+	copyBatch := func(failAt int64) func(context.Context, *sql.Tx, []int64) error {
+		return func(ctx context.Context, tx *sql.Tx, batch []int64) error {
+			for _, id := range batch {
+				if _, err := tx.ExecContext(ctx, `INSERT INTO processed (id) VALUES (?)`, id); err != nil {
+					return err
+				}
+				if id == failAt {
+					return boom
+				}
+			}
+			return nil
+		}
+	}
+
+	// The second page fails halfway: its insert and checkpoint are rolled back.
+	if err := w.Walk(ctx, copyBatch(3)); !errors.Is(err, boom) {
+		t.Fatalf("want boom, got %v", err)
+	}
+	if got, _ := store.Load(ctx, "copy-posts"); got != keyset.EncodeInt64Cursor(2) {
+		t.Fatalf("checkpoint must stay at the last committed page, got %q", got)
+	}
+
+	// A restart resumes after the last committed page; no id is inserted twice.
+	if err := w.Walk(ctx, copyBatch(-1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, err := ksql.Query(ctx, db, `SELECT id FROM processed ORDER BY id`, nil, scanID)
+	if err != nil {
+		t.Fatalf("query processed: %v", err)
+	}
+	if !slices.Equal(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("want every id exactly once, got %v", ids)
+	}
+}
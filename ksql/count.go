@@ -0,0 +1,83 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// CountQuery returns the statement that counts the rows of base according to
+// opts. base is the same SELECT prefix passed to the Query builders, without
+// keyset window or LIMIT:
+//
+//	CountExact:    SELECT COUNT(*) FROM (base) AS keyset_count
+//	CountCapped:   SELECT COUNT(*) FROM (base LIMIT n) AS keyset_count
+//	CountEstimate: EXPLAIN (FORMAT JSON) base   -- PostgreSQL only
+func CountQuery(base string, opts keyset.CountOptions) string {
+	opts.EnsureDefaults()
+	switch opts.Mode {
+	case keyset.CountCapped:
+		return fmt.Sprintf("SELECT COUNT(*) FROM (%s LIMIT %d) AS keyset_count", base, opts.Limit)
+	case keyset.CountEstimate:
+		return "EXPLAIN (FORMAT JSON) " + base
+	default:
+		return fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS keyset_count", base)
+	}
+}
+
+// Count counts the rows of base (see CountQuery). args are bound to base.
+func Count(ctx context.Context, db Querier, base string, args []any, opts keyset.CountOptions) (keyset.Total, error) {
+	opts.EnsureDefaults()
+	query := CountQuery(base, opts)
+
+	if opts.Mode == keyset.CountEstimate {
+		plans, err := Query(ctx, db, query, args, func(rows *sql.Rows) ([]byte, error) {
+			var b []byte
+			err := rows.Scan(&b)
+			return b, err
+		})
+		if err != nil {
+			return keyset.Total{}, err
+		}
+		if len(plans) == 0 {
+			return keyset.Total{}, errors.New("ksql: count estimate: empty plan")
+		}
+		n, err := keyset.ParsePlanRows(plans[0])
+		if err != nil {
+			return keyset.Total{}, err
+		}
+		return keyset.NewTotal(n, opts), nil
+	}
+
+	counts, err := Query(ctx, db, query, args, func(rows *sql.Rows) (int64, error) {
+		var n int64
+		err := rows.Scan(&n)
+		return n, err
+	})
+	if err != nil {
+		return keyset.Total{}, err
+	}
+	if len(counts) == 0 {
+		return keyset.Total{}, errors.New("ksql: count: no rows")
+	}
+	return keyset.NewTotal(counts[0], opts), nil
+}
+
+// Counter returns a keyset.CountFunc that runs Count for base.
+func Counter(db Querier, base string, args []any, opts keyset.CountOptions) keyset.CountFunc {
+	return func(ctx context.Context) (keyset.Total, error) {
+		return Count(ctx, db, base, args, opts)
+	}
+}
+
+// PaginateWithTotal is like Paginate and also attaches the total of base to
+// the result (see keyset.PaginateWithTotal):
+//
+//	res, err := ksql.PaginateWithTotal(ctx, db, page, build, scanPost, postCursor,
+//		ksql.Counter(db, base, nil, keyset.CountOptions{Mode: keyset.CountCapped}))
+func PaginateWithTotal[T any](ctx context.Context, db Querier, p keyset.Page, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T], count keyset.CountFunc) (keyset.Result[T], error) {
+	return keyset.PaginateWithTotal(ctx, p, Fetch(db, build, scan), cursor, count)
+}
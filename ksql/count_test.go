@@ -0,0 +1,62 @@
+package ksql_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestCount_ExactAndCapped(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openSQLite(t, 25)
+	base := `SELECT id FROM posts WHERE id > ?`
+
+	exact, err := ksql.Count(ctx, db, base, []any{5}, keyset.CountOptions{})
+	if err != nil || exact.Count != 20 || !exact.Exact() {
+		t.Fatalf("want exact 20, got (%+v, %v)", exact, err)
+	}
+
+	capped, err := ksql.Count(ctx, db, base, []any{5}, keyset.CountOptions{Mode: keyset.CountCapped, Limit: 10})
+	if err != nil || capped.Count != 10 || !capped.Capped {
+		t.Fatalf("want capped 10, got (%+v, %v)", capped, err)
+	}
+}
+
+func TestCount_Estimate(t *testing.T) {
+	t.Parallel()
+	db, mock := newMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`EXPLAIN (FORMAT JSON) SELECT id FROM posts`)).
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(`[{"Plan": {"Plan Rows": 12400}}]`))
+
+	tot, err := ksql.Count(context.Background(), db, `SELECT id FROM posts`, nil, keyset.CountOptions{Mode: keyset.CountEstimate})
+	if err != nil || tot.Count != 12400 || tot.Mode != keyset.CountEstimate {
+		t.Fatalf("want estimate 12400, got (%+v, %v)", tot, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestPaginateWithTotal(t *testing.T) {
+	t.Parallel()
+	db := openSQLite(t, 7)
+
+	build := func(p keyset.Page) (string, []any) {
+		return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderQuestion)
+	}
+	count := ksql.Counter(db, `SELECT id FROM posts`, nil, keyset.CountOptions{})
+	res, err := ksql.PaginateWithTotal(context.Background(), db, keyset.Page{Limit: 3}, build, scanID, keyset.EncodeInt64Cursor, count)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Total == nil || res.Total.Count != 7 || len(res.Items) != 3 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -4,4 +4,8 @@ go 1.23.0
 
 replace github.com/mickamy/go-keyset => ..
 
-require github.com/mickamy/go-keyset v0.0.0
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/mickamy/go-keyset v0.0.0
+)
@@ -0,0 +1,77 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Querier is the subset of *sql.DB, *sql.Tx and *sql.Conn used to run page queries.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// BuildFunc builds the SQL and args for a single page, typically a closure over
+// QueryByID, QueryByTime or QueryByTimeAndID.
+type BuildFunc func(p keyset.Page) (string, []any)
+
+// ScanFunc scans the current row into a value.
+type ScanFunc[T any] func(rows *sql.Rows) (T, error)
+
+// Query runs query and scans every returned row with scan.
+func Query[T any](ctx context.Context, db Querier, query string, args []any, scan ScanFunc[T]) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ksql: query: %w", err)
+	}
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ksql: scan: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ksql: rows: %w", err)
+	}
+	return items, nil
+}
+
+// Fetch returns a keyset.FetchFunc that builds the page query with build,
+// runs it against db and returns the rows in display order.
+func Fetch[T any](db Querier, build BuildFunc, scan ScanFunc[T]) keyset.FetchFunc[T] {
+	return func(ctx context.Context, p keyset.Page) ([]T, error) {
+		query, args := build(p)
+		items, err := Query(ctx, db, query, args, scan)
+		if err != nil {
+			return nil, err
+		}
+		return keyset.NormalizePageResult(p, items), nil
+	}
+}
+
+// Rows returns an iterator over every row reachable from p, driving the cursor
+// automatically. It is intended for batch jobs and backfills:
+//
+//	build := func(p keyset.Page) (string, []any) {
+//		return ksql.QueryByID(`SELECT id, title FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderDollar)
+//	}
+//	for post, err := range ksql.Rows(ctx, db, keyset.Page{Limit: 500}, build, scanPost, postCursor) {
+//		if err != nil { ... }
+//	}
+//
+// See keyset.Pages for the stop and error semantics.
+func Rows[T any](ctx context.Context, db Querier, p keyset.Page, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) iter.Seq2[T, error] {
+	return keyset.All(ctx, p, Fetch(db, build, scan), cursor)
+}
+
+// Pages is like Rows but yields one slice per page.
+func Pages[T any](ctx context.Context, db Querier, p keyset.Page, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) iter.Seq2[[]T, error] {
+	return keyset.Pages(ctx, p, Fetch(db, build, scan), cursor)
+}
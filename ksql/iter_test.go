@@ -0,0 +1,81 @@
+package ksql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func scanID(rows *sql.Rows) (int64, error) {
+	var id int64
+	err := rows.Scan(&id)
+	return id, err
+}
+
+func buildByID(p keyset.Page) (string, []any) {
+	return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderDollar)
+}
+
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, mock
+}
+
+func TestRows_DrivesCursor(t *testing.T) {
+	t.Parallel()
+	db, mock := newMock(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM posts ORDER BY id ASC LIMIT $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM posts WHERE id > $1 ORDER BY id ASC LIMIT $2`)).
+		WithArgs(int64(2), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	var got []int64
+	for id, err := range ksql.Rows(context.Background(), db, keyset.Page{Limit: 2}, buildByID, scanID, keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, id)
+	}
+
+	if !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Fatalf("rows mismatch: %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestPages_SurfacesQueryError(t *testing.T) {
+	t.Parallel()
+	db, mock := newMock(t)
+
+	boom := errors.New("boom")
+	mock.ExpectQuery(`SELECT id FROM posts`).WillReturnError(boom)
+
+	n := 0
+	for batch, err := range ksql.Pages(context.Background(), db, keyset.Page{Limit: 2}, buildByID, scanID, keyset.EncodeInt64Cursor) {
+		n++
+		if batch != nil || !errors.Is(err, boom) {
+			t.Fatalf("want (nil, boom), got (%v, %v)", batch, err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("want exactly one yield, got %d", n)
+	}
+}
@@ -31,6 +31,7 @@ package ksql
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mickamy/go-keyset"
 )
@@ -48,48 +49,54 @@ func PlaceholderDollar(n int) string { return fmt.Sprintf("$%d", n) }
 
 // QueryByID builds a keyset-paginated SQL statement for a single integer key column.
 // - base: a SELECT ... FROM ... [WHERE ...] prefix (without ORDER/LIMIT).
-// - p:    pagination state (Limit/Dir/Cursor).
+// - p:    pagination state (Limit/Dir/Cursor/Until).
 // - ord:  base sort order (Ascending/Descending).
 // - col:  name of the integer key column.
 // - ph:   placeholder strategy (e.g., PlaceholderDollar for Postgres).
 //
-// The returned SQL appends a stable WHERE window (if a valid cursor is present),
-// an ORDER BY clause according to the effective order, and a LIMIT clause.
+// The returned SQL appends a stable WHERE window (if a valid cursor is present;
+// "<=" / ">=" when p.Bound is keyset.Inclusive),
+// an upper window (if a valid p.Until is present), "col <= :snapshot" (if a
+// valid p.Snapshot is present), an ORDER BY clause according
+// to the effective order, and a LIMIT clause.
 // The returned args are the bound variables in order (window values followed by limit).
 func QueryByID(base string, p keyset.Page, ord keyset.Order, col string, ph Placeholder) (string, []any) {
 	p.EnsureDefaults()
-	eff := keyset.EffectiveOrder(ord, p.Dir)
+	b := newBuilder(base, ph)
+	b.windowByID(p, ord, col)
+	b.orderLimit([]string{col}, keyset.EffectiveOrder(ord, p.Dir), p.Limit)
+	return b.String(), b.args
+}
 
-	var (
-		sqlBuilder strings.Builder
-		args       []any
-		argIdx     = 1
-	)
-	sqlBuilder.WriteString(base)
+// CountByID builds a statement counting the rows in the window of QueryByID,
+// ignoring p.Limit:
+//
+//	SELECT COUNT(*) FROM (base WHERE id > $1) AS keyset_window
+func CountByID(base string, p keyset.Page, ord keyset.Order, col string, ph Placeholder) (string, []any) {
+	p.EnsureDefaults()
+	b := newBuilder(base, ph)
+	b.windowByID(p, ord, col)
+	return b.count(), b.args
+}
 
+func (b *builder) windowByID(p keyset.Page, ord keyset.Order, col string) {
 	// WHERE window (if cursor is valid)
 	if p.Cursor != "" {
 		if id, err := keyset.DecodeInt64Cursor(p.Cursor); err == nil {
-			where := fmt.Sprintf("%s %s %s", col, eff.InequalityOp(), ph(argIdx))
-			sqlBuilder.WriteString(appendWhere(base, where))
-			args = append(args, id)
-			argIdx++
+			b.where(fmt.Sprintf("%s %s %s", col, keyset.EffectiveOrder(ord, p.Dir).BoundOp(p.Bound), b.bind(id)))
 		}
 		// On invalid cursor: fail open (no WHERE), consistent with kgorm behavior.
 	}
-
-	// ORDER BY
-	sqlBuilder.WriteString(" ORDER BY ")
-	sqlBuilder.WriteString(col)
-	sqlBuilder.WriteString(" ")
-	sqlBuilder.WriteString(eff.SQLKeyword())
-
-	// LIMIT
-	sqlBuilder.WriteString(" LIMIT ")
-	sqlBuilder.WriteString(ph(argIdx))
-	args = append(args, p.Limit)
-
-	return sqlBuilder.String(), args
+	if p.Until != "" {
+		if id, err := keyset.DecodeInt64Cursor(p.Until); err == nil {
+			b.where(fmt.Sprintf("%s %s %s", col, keyset.UntilOrder(ord, p.Dir).BoundOp(p.UntilBound), b.bind(id)))
+		}
+	}
+	if p.Snapshot != "" {
+		if id, err := keyset.DecodeInt64Cursor(p.Snapshot); err == nil {
+			b.where(fmt.Sprintf("%s <= %s", col, b.bind(id)))
+		}
+	}
 }
 
 // QueryByTime builds a keyset-paginated SQL statement for a single time column.
@@ -97,88 +104,152 @@ func QueryByID(base string, p keyset.Page, ord keyset.Order, col string, ph Plac
 // See QueryByID for parameter semantics.
 func QueryByTime(base string, p keyset.Page, ord keyset.Order, col string, ph Placeholder) (string, []any) {
 	p.EnsureDefaults()
-	eff := keyset.EffectiveOrder(ord, p.Dir)
+	b := newBuilder(base, ph)
+	b.windowByTime(p, ord, col)
+	b.orderLimit([]string{col}, keyset.EffectiveOrder(ord, p.Dir), p.Limit)
+	return b.String(), b.args
+}
 
-	var (
-		sqlBuilder strings.Builder
-		args       []any
-		argIdx     = 1
-	)
-	sqlBuilder.WriteString(base)
+// CountByTime builds a statement counting the rows in the window of
+// QueryByTime, ignoring p.Limit. See CountByID.
+func CountByTime(base string, p keyset.Page, ord keyset.Order, col string, ph Placeholder) (string, []any) {
+	p.EnsureDefaults()
+	b := newBuilder(base, ph)
+	b.windowByTime(p, ord, col)
+	return b.count(), b.args
+}
 
+func (b *builder) windowByTime(p keyset.Page, ord keyset.Order, col string) {
 	if p.Cursor != "" {
 		if tm, err := keyset.DecodeTimeCursor(p.Cursor); err == nil {
-			where := fmt.Sprintf("%s %s %s", col, eff.InequalityOp(), ph(argIdx))
-			sqlBuilder.WriteString(appendWhere(base, where))
-			args = append(args, tm)
-			argIdx++
+			b.where(fmt.Sprintf("%s %s %s", col, keyset.EffectiveOrder(ord, p.Dir).BoundOp(p.Bound), b.bind(tm)))
+		}
+	}
+	if p.Until != "" {
+		if tm, err := keyset.DecodeTimeCursor(p.Until); err == nil {
+			b.where(fmt.Sprintf("%s %s %s", col, keyset.UntilOrder(ord, p.Dir).BoundOp(p.UntilBound), b.bind(tm)))
+		}
+	}
+	if p.Snapshot != "" {
+		if tm, err := keyset.DecodeTimeCursor(p.Snapshot); err == nil {
+			b.where(fmt.Sprintf("%s <= %s", col, b.bind(tm)))
 		}
 	}
-
-	sqlBuilder.WriteString(" ORDER BY ")
-	sqlBuilder.WriteString(col)
-	sqlBuilder.WriteString(" ")
-	sqlBuilder.WriteString(eff.SQLKeyword())
-
-	sqlBuilder.WriteString(" LIMIT ")
-	sqlBuilder.WriteString(ph(argIdx))
-	args = append(args, p.Limit)
-
-	return sqlBuilder.String(), args
 }
 
 // QueryByTimeAndID builds a keyset-paginated SQL statement for the composite key (time, id).
-// The cursor must be produced by keyset.EncodeTimeAndInt64Cursor.
+// The cursor must be produced by keyset.EncodeTimeAndInt64Cursor, or by
+// keyset.EncodeTimePrefixCursor to seek by time alone (time < :t for DESC).
 // The stable window is:
 //
 //	DESC: (time < :t) OR (time = :t AND id < :id)
 //	ASC : (time > :t) OR (time = :t AND id > :id)
 //
+// With p.Bound set to keyset.Inclusive the id comparisons become "<=" / ">=",
+// so the cursor row itself is returned as well.
+//
+// A valid p.Until adds the mirrored window on the other side, e.g. for DESC:
+//
+//	(time > :u) OR (time = :u AND id > :uid)
+//
 // The function appends WHERE (if cursor valid), composite ORDER BY, and LIMIT.
 func QueryByTimeAndID(base string, p keyset.Page, ord keyset.Order, timeCol, idCol string, ph Placeholder) (string, []any) {
 	p.EnsureDefaults()
-	eff := keyset.EffectiveOrder(ord, p.Dir)
+	b := newBuilder(base, ph)
+	b.windowByTimeAndID(p, ord, timeCol, idCol)
+	b.orderLimit([]string{timeCol, idCol}, keyset.EffectiveOrder(ord, p.Dir), p.Limit)
+	return b.String(), b.args
+}
 
-	var (
-		sqlBuilder strings.Builder
-		args       []any
-		argIdx     = 1
-	)
-	sqlBuilder.WriteString(base)
+// CountByTimeAndID builds a statement counting the rows in the window of
+// QueryByTimeAndID, ignoring p.Limit. See CountByID.
+func CountByTimeAndID(base string, p keyset.Page, ord keyset.Order, timeCol, idCol string, ph Placeholder) (string, []any) {
+	p.EnsureDefaults()
+	b := newBuilder(base, ph)
+	b.windowByTimeAndID(p, ord, timeCol, idCol)
+	return b.count(), b.args
+}
 
+func (b *builder) windowByTimeAndID(p keyset.Page, ord keyset.Order, timeCol, idCol string) {
 	if p.Cursor != "" {
-		if tm, id, err := keyset.DecodeTimeAndInt64Cursor(p.Cursor); err == nil {
+		if tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Cursor); err == nil {
 			// Build stable WHERE using the effective order.
-			tCmp, idCmp := ">", ">"
-			if eff == keyset.Descending {
-				tCmp, idCmp = "<", "<"
-			}
-			where := fmt.Sprintf("(%s %s %s) OR (%s = %s AND %s %s %s)",
-				timeCol, tCmp, ph(argIdx),
-				timeCol, ph(argIdx+1), idCol, idCmp, ph(argIdx+2),
-			)
-			sqlBuilder.WriteString(appendWhere(base, where))
-			args = append(args, tm, tm, id)
-			argIdx += 3
+			b.where(b.timeAndID(timeCol, idCol, keyset.EffectiveOrder(ord, p.Dir), p.Bound, tm, id, full))
 		}
 	}
+	if p.Until != "" {
+		if tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Until); err == nil {
+			b.where(b.timeAndID(timeCol, idCol, keyset.UntilOrder(ord, p.Dir), p.UntilBound, tm, id, full))
+		}
+	}
+	if p.Snapshot != "" {
+		if tm, id, full, err := keyset.DecodeTimeAndInt64PrefixCursor(p.Snapshot); err == nil {
+			// Keys at or below the snapshot: the inclusive DESC window.
+			b.where(b.timeAndID(timeCol, idCol, keyset.Descending, keyset.Inclusive, tm, id, full))
+		}
+	}
+}
+
+// builder accumulates a paginated statement and its bound args,
+// numbering placeholders in the order values are bound.
+type builder struct {
+	sb       strings.Builder
+	args     []any
+	ph       Placeholder
+	hasWhere bool
+}
+
+func newBuilder(base string, ph Placeholder) *builder {
+	b := &builder{ph: ph, hasWhere: hasWhere(strings.ToLower(base))}
+	b.sb.WriteString(base)
+	return b
+}
+
+// bind records v as the next argument and returns its placeholder.
+func (b *builder) bind(v any) string {
+	b.args = append(b.args, v)
+	return b.ph(len(b.args))
+}
+
+// where appends cond with " WHERE " or " AND " as appropriate.
+func (b *builder) where(cond string) {
+	if b.hasWhere {
+		b.sb.WriteString(" AND ")
+	} else {
+		b.sb.WriteString(" WHERE ")
+		b.hasWhere = true
+	}
+	b.sb.WriteString(cond)
+}
+
+// timeAndID renders the composite window, parenthesized as a whole so that it
+// composes with other AND-ed conditions. A partial (time-only) key bounds the
+// time column alone.
+func (b *builder) timeAndID(timeCol, idCol string, ord keyset.Order, bound keyset.Bound, tm time.Time, id int64, full bool) string {
+	if !full {
+		return fmt.Sprintf("%s %s %s", timeCol, ord.BoundOp(bound), b.bind(tm))
+	}
+	return fmt.Sprintf("((%s %s %s) OR (%s = %s AND %s %s %s))",
+		timeCol, ord.InequalityOp(), b.bind(tm),
+		timeCol, b.bind(tm), idCol, ord.BoundOp(bound), b.bind(id),
+	)
+}
+
+// orderLimit appends ORDER BY cols (all in ord) and LIMIT.
+func (b *builder) orderLimit(cols []string, ord keyset.Order, limit int) {
+	b.sb.WriteString(" ORDER BY ")
+	b.sb.WriteString(keyset.OrderClause(cols, ord))
+	b.sb.WriteString(" LIMIT ")
+	b.sb.WriteString(b.bind(limit))
+}
+
+// count wraps the statement so far in SELECT COUNT(*).
+func (b *builder) count() string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS keyset_window", b.sb.String())
+}
 
-	// ORDER BY time, id
-	sqlBuilder.WriteString(" ORDER BY ")
-	sqlBuilder.WriteString(timeCol)
-	sqlBuilder.WriteString(" ")
-	sqlBuilder.WriteString(eff.SQLKeyword())
-	sqlBuilder.WriteString(", ")
-	sqlBuilder.WriteString(idCol)
-	sqlBuilder.WriteString(" ")
-	sqlBuilder.WriteString(eff.SQLKeyword())
-
-	// LIMIT
-	sqlBuilder.WriteString(" LIMIT ")
-	sqlBuilder.WriteString(ph(argIdx))
-	args = append(args, p.Limit)
-
-	return sqlBuilder.String(), args
+func (b *builder) String() string {
+	return b.sb.String()
 }
 
 // appendWhere decides whether to append " WHERE <cond>" or " AND <cond>"
@@ -216,3 +216,161 @@ func TestQueryByTimeAndID_AppendWhereWhenBaseHasWhere(t *testing.T) {
 		t.Fatalf("missing ORDER DESC, DESC: %s", sql)
 	}
 }
+
+func TestQuery_BoundedByUntil(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ID DESC DirNext exclusive", func(t *testing.T) {
+		t.Parallel()
+		p := keyset.Page{
+			Cursor: keyset.EncodeInt64Cursor(100),
+			Until:  keyset.EncodeInt64Cursor(50),
+			Limit:  10,
+		}
+		sql, args := ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderDollar)
+
+		if !strings.Contains(sql, "WHERE id < $1 AND id > $2 ORDER BY id DESC LIMIT $3") {
+			t.Fatalf("missing bounded window: %s", sql)
+		}
+		if len(args) != 3 || args[0] != int64(100) || args[1] != int64(50) || args[2] != 10 {
+			t.Fatalf("args mismatch: %v", args)
+		}
+	})
+
+	t.Run("time ASC DirPrev inclusive, no cursor", func(t *testing.T) {
+		t.Parallel()
+		ts := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		p := keyset.Page{Until: keyset.EncodeTimeCursor(ts), UntilBound: keyset.Inclusive, Limit: 5, Dir: keyset.DirPrev}
+		sql, args := ksql.QueryByTime(`SELECT * FROM posts`, p, keyset.Ascending, "created_at", ksql.PlaceholderDollar)
+
+		// DirPrev walks backward (DESC), so Until bounds from below.
+		if !strings.Contains(sql, "WHERE created_at >= $1 ORDER BY created_at DESC LIMIT $2") {
+			t.Fatalf("missing inclusive until window: %s", sql)
+		}
+		if len(args) != 2 || args[0] != ts || args[1] != 5 {
+			t.Fatalf("args mismatch: %v", args)
+		}
+	})
+
+	t.Run("composite DESC DirNext inclusive", func(t *testing.T) {
+		t.Parallel()
+		from := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		p := keyset.Page{
+			Cursor:     keyset.EncodeTimeAndInt64Cursor(from, 9),
+			Until:      keyset.EncodeTimeAndInt64Cursor(to, 3),
+			UntilBound: keyset.Inclusive,
+			Limit:      20,
+		}
+		sql, args := ksql.QueryByTimeAndID(`SELECT * FROM posts WHERE tenant_id = 1`, p, keyset.Descending, "created_at", "id", ksql.PlaceholderDollar)
+
+		want := " WHERE tenant_id = 1" +
+			" AND ((created_at < $1) OR (created_at = $2 AND id < $3))" +
+			" AND ((created_at > $4) OR (created_at = $5 AND id >= $6))" +
+			" ORDER BY created_at DESC, id DESC LIMIT $7"
+		if !strings.HasSuffix(sql, want) {
+			t.Fatalf("unexpected bounded composite window:\n got: %s\nwant suffix: %s", sql, want)
+		}
+		if len(args) != 7 || args[3] != to || args[4] != to || args[5] != int64(3) || args[6] != 20 {
+			t.Fatalf("args mismatch: %v", args)
+		}
+	})
+}
+
+func TestQuery_InclusiveCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ID ASC DirNext", func(t *testing.T) {
+		t.Parallel()
+		p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(10), Bound: keyset.Inclusive, Limit: 3}
+		sql, args := ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderDollar)
+
+		if !strings.Contains(sql, "WHERE id >= $1 ORDER BY id ASC") {
+			t.Fatalf("missing inclusive window: %s", sql)
+		}
+		if len(args) != 2 || args[0] != int64(10) || args[1] != 3 {
+			t.Fatalf("args mismatch: %v", args)
+		}
+	})
+
+	t.Run("composite DESC DirPrev", func(t *testing.T) {
+		t.Parallel()
+		ts := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+		p := keyset.Page{Cursor: keyset.EncodeTimeAndInt64Cursor(ts, 5), Bound: keyset.Inclusive, Limit: 3, Dir: keyset.DirPrev}
+		sql, _ := ksql.QueryByTimeAndID(`SELECT * FROM posts`, p, keyset.Descending, "created_at", "id", ksql.PlaceholderDollar)
+
+		// The time comparison stays strict; only the id tie-breaker includes the row.
+		if !strings.Contains(sql, "WHERE ((created_at > $1) OR (created_at = $2 AND id >= $3))") {
+			t.Fatalf("missing inclusive composite window: %s", sql)
+		}
+		if !strings.Contains(sql, "ORDER BY created_at ASC, id ASC") {
+			t.Fatalf("missing ORDER ASC for DirPrev: %s", sql)
+		}
+	})
+}
+
+func TestQueryByTimeAndID_PartialKey(t *testing.T) {
+	t.Parallel()
+	ts := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("seek DESC", func(t *testing.T) {
+		t.Parallel()
+		p := keyset.Page{Cursor: keyset.EncodeTimePrefixCursor(ts), Limit: 3}
+		sql, args := ksql.QueryByTimeAndID(`SELECT * FROM posts`, p, keyset.Descending, "created_at", "id", ksql.PlaceholderDollar)
+
+		if !strings.Contains(sql, "WHERE created_at < $1 ORDER BY created_at DESC, id DESC LIMIT $2") {
+			t.Fatalf("missing leading-column window: %s", sql)
+		}
+		if len(args) != 2 || args[0] != ts || args[1] != 3 {
+			t.Fatalf("args mismatch: %v", args)
+		}
+	})
+
+	t.Run("inclusive until", func(t *testing.T) {
+		t.Parallel()
+		p := keyset.Page{
+			Cursor:     keyset.EncodeTimeAndInt64Cursor(ts, 9),
+			Until:      keyset.EncodeTimePrefixCursor(ts.AddDate(0, -1, 0)),
+			UntilBound: keyset.Inclusive,
+			Limit:      3,
+		}
+		sql, _ := ksql.QueryByTimeAndID(`SELECT * FROM posts`, p, keyset.Descending, "created_at", "id", ksql.PlaceholderDollar)
+
+		if !strings.Contains(sql, "WHERE ((created_at < $1) OR (created_at = $2 AND id < $3)) AND created_at >= $4") {
+			t.Fatalf("missing partial until window: %s", sql)
+		}
+	})
+}
+
+func TestQueryByID_TokenCarriesDirection(t *testing.T) {
+	t.Parallel()
+	p := keyset.Page{Cursor: keyset.PrevToken(keyset.EncodeInt64Cursor(10), 5)}
+	sql, args := ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderDollar)
+
+	if !strings.Contains(sql, "WHERE id > $1 ORDER BY id ASC LIMIT $2") {
+		t.Fatalf("missing DirPrev window from token: %s", sql)
+	}
+	if len(args) != 2 || args[0] != int64(10) || args[1] != 5 {
+		t.Fatalf("args mismatch: %v", args)
+	}
+}
+
+func TestQuery_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(10), Dir: keyset.DirPrev, Snapshot: keyset.EncodeInt64Cursor(42), Limit: 5}
+	sql, args := ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderDollar)
+	if !strings.Contains(sql, "WHERE id > $1 AND id <= $2 ORDER BY id ASC LIMIT $3") {
+		t.Fatalf("missing snapshot bound: %s", sql)
+	}
+	if len(args) != 3 || args[1] != int64(42) {
+		t.Fatalf("args mismatch: %v", args)
+	}
+
+	ts := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	p = keyset.Page{Snapshot: keyset.EncodeTimeAndInt64Cursor(ts, 7), Limit: 5}
+	sql, _ = ksql.QueryByTimeAndID(`SELECT * FROM posts`, p, keyset.Ascending, "created_at", "id", ksql.PlaceholderDollar)
+	if !strings.Contains(sql, "WHERE ((created_at < $1) OR (created_at = $2 AND id <= $3)) ORDER BY created_at ASC, id ASC") {
+		t.Fatalf("missing composite snapshot bound: %s", sql)
+	}
+}
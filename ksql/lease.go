@@ -0,0 +1,341 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+var (
+	// ErrNoLease is returned by Coordinator.Acquire when no range is available.
+	ErrNoLease = errors.New("ksql: no range available to lease")
+	// ErrLeaseLost is returned when a lease expired and was taken over by another worker.
+	ErrLeaseLost = errors.New("ksql: lease lost")
+)
+
+// DefaultLeaseTable is the table name used by NewCoordinator when none is given.
+const DefaultLeaseTable = "keyset_leases"
+
+// Coordinator distributes the key ranges of scan jobs across processes.
+//
+// Every range is a row in a lease table. Workers lease a range for a limited
+// time, extend the lease with heartbeats while they page through it, and mark
+// it done at the end. A lease that is not extended in time expires and can be
+// stolen by another worker, which resumes from the range's checkpoint.
+type Coordinator struct {
+	db          DBTX
+	table       string
+	ph          Placeholder
+	ttl         time.Duration
+	checkpoints keyset.CheckpointStore
+}
+
+// NewCoordinator returns a Coordinator storing leases in table (DefaultLeaseTable
+// if empty) and per-range cursors in checkpoints, typically a CheckpointTable on
+// the same database. Leases expire ttl after they were acquired or last extended.
+// It panics if ttl is not positive.
+func NewCoordinator(db DBTX, table string, ph Placeholder, ttl time.Duration, checkpoints keyset.CheckpointStore) *Coordinator {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("ksql: non-positive lease ttl %v", ttl))
+	}
+	if table == "" {
+		table = DefaultLeaseTable
+	}
+	return &Coordinator{db: db, table: table, ph: ph, ttl: ttl, checkpoints: checkpoints}
+}
+
+// Lease is a worker's time-limited claim on one range of a job.
+type Lease struct {
+	Job       string
+	RangeID   int
+	Range     keyset.Int64Range
+	Owner     string
+	ExpiresAt time.Time
+}
+
+// CheckpointKey returns the key under which the range's cursor is checkpointed.
+func (l *Lease) CheckpointKey() string {
+	return fmt.Sprintf("%s/%d", l.Job, l.RangeID)
+}
+
+// CreateTable creates the lease table if it does not exist yet.
+// Expiry times are stored as Unix nanoseconds to stay portable across databases.
+func (c *Coordinator) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"job VARCHAR(255) NOT NULL, "+
+		"range_id INTEGER NOT NULL, "+
+		"min_key BIGINT NOT NULL, "+
+		"max_key BIGINT NOT NULL, "+
+		"owner VARCHAR(255), "+
+		"expires_at BIGINT NOT NULL DEFAULT 0, "+
+		"done INTEGER NOT NULL DEFAULT 0, "+
+		"PRIMARY KEY (job, range_id))", c.table)
+	if _, err := c.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("ksql: create lease table: %w", err)
+	}
+	return nil
+}
+
+// Plan registers the ranges of job, typically produced by SplitByMinMax or
+// SplitBySample. A job is planned once, with a single multi-row INSERT: if it
+// already has ranges, the stored plan is kept and ranges is ignored. Every
+// worker may therefore call Plan on startup, even when their splits differ
+// (sampling is nondeterministic and min/max move as rows are inserted).
+func (c *Coordinator) Plan(ctx context.Context, job string, ranges []keyset.Int64Range) error {
+	if len(ranges) == 0 {
+		return nil
+	}
+	if planned, err := c.planned(ctx, job); err != nil || planned {
+		return err
+	}
+
+	values := make([]string, len(ranges))
+	args := make([]any, 0, 4*len(ranges))
+	for i, r := range ranges {
+		n := len(args)
+		values[i] = fmt.Sprintf("(%s, %s, %s, %s)", c.ph(n+1), c.ph(n+2), c.ph(n+3), c.ph(n+4))
+		args = append(args, job, i, r.Min, r.Max)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (job, range_id, min_key, max_key) VALUES %s", c.table, strings.Join(values, ", "))
+	if _, err := c.db.ExecContext(ctx, insert, args...); err != nil {
+		// Another worker may have planned the same job concurrently.
+		if planned, perr := c.planned(ctx, job); perr == nil && planned {
+			return nil
+		}
+		return fmt.Errorf("ksql: plan job %q: %w", job, err)
+	}
+	return nil
+}
+
+// planned reports whether job has any ranges.
+func (c *Coordinator) planned(ctx context.Context, job string) (bool, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE job = %s", c.table, c.ph(1))
+	counts, err := Query(ctx, c.db, query, []any{job}, func(rows *sql.Rows) (int, error) {
+		var n int
+		err := rows.Scan(&n)
+		return n, err
+	})
+	if err != nil {
+		return false, err
+	}
+	return counts[0] > 0, nil
+}
+
+// Acquire leases the first range of job that is neither done nor held by a
+// live lease; expired leases are stolen. It returns ErrNoLease when nothing
+// is available right now.
+func (c *Coordinator) Acquire(ctx context.Context, job, owner string) (*Lease, error) {
+	now := time.Now()
+	query := fmt.Sprintf("SELECT range_id, min_key, max_key FROM %s "+
+		"WHERE job = %s AND done = 0 AND (owner IS NULL OR expires_at < %s) ORDER BY range_id",
+		c.table, c.ph(1), c.ph(2))
+	candidates, err := Query(ctx, c.db, query, []any{job, now.UnixNano()}, func(rows *sql.Rows) (Lease, error) {
+		l := Lease{Job: job}
+		err := rows.Scan(&l.RangeID, &l.Range.Min, &l.Range.Max)
+		return l, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim with a conditional UPDATE so that concurrent workers racing for the
+	// same range cannot both win.
+	claim := fmt.Sprintf("UPDATE %s SET owner = %s, expires_at = %s "+
+		"WHERE job = %s AND range_id = %s AND done = 0 AND (owner IS NULL OR expires_at < %s)",
+		c.table, c.ph(1), c.ph(2), c.ph(3), c.ph(4), c.ph(5))
+	for _, l := range candidates {
+		expires := now.Add(c.ttl)
+		ok, err := c.exec1(ctx, claim, owner, expires.UnixNano(), job, l.RangeID, now.UnixNano())
+		if err != nil {
+			return nil, fmt.Errorf("ksql: acquire lease: %w", err)
+		}
+		if ok {
+			l.Owner, l.ExpiresAt = owner, expires
+			return &l, nil
+		}
+	}
+	return nil, ErrNoLease
+}
+
+// Heartbeat extends l by the coordinator's ttl. It returns ErrLeaseLost if
+// the lease was taken over by another worker or the range is already done.
+func (c *Coordinator) Heartbeat(ctx context.Context, l *Lease) error {
+	expires := time.Now().Add(c.ttl)
+	query := fmt.Sprintf("UPDATE %s SET expires_at = %s WHERE job = %s AND range_id = %s AND owner = %s AND done = 0",
+		c.table, c.ph(1), c.ph(2), c.ph(3), c.ph(4))
+	ok, err := c.exec1(ctx, query, expires.UnixNano(), l.Job, l.RangeID, l.Owner)
+	if err != nil {
+		return fmt.Errorf("ksql: heartbeat: %w", err)
+	}
+	if !ok {
+		return ErrLeaseLost
+	}
+	l.ExpiresAt = expires
+	return nil
+}
+
+// Commit records cursor as the range's checkpoint after verifying (and
+// extending) the lease. Processing is at-least-once: a worker that loses its
+// lease mid-page may overlap with the worker that stole it.
+func (c *Coordinator) Commit(ctx context.Context, l *Lease, cursor string) error {
+	if err := c.Heartbeat(ctx, l); err != nil {
+		return err
+	}
+	if err := c.checkpoints.Save(ctx, l.CheckpointKey(), cursor); err != nil {
+		return fmt.Errorf("ksql: commit checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Complete marks the leased range as done and releases the lease.
+func (c *Coordinator) Complete(ctx context.Context, l *Lease) error {
+	query := fmt.Sprintf("UPDATE %s SET done = 1, owner = NULL WHERE job = %s AND range_id = %s AND owner = %s AND done = 0",
+		c.table, c.ph(1), c.ph(2), c.ph(3))
+	ok, err := c.exec1(ctx, query, l.Job, l.RangeID, l.Owner)
+	if err != nil {
+		return fmt.Errorf("ksql: complete lease: %w", err)
+	}
+	if !ok {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Remaining returns the number of ranges of job that are not done yet.
+func (c *Coordinator) Remaining(ctx context.Context, job string) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE job = %s AND done = 0", c.table, c.ph(1))
+	counts, err := Query(ctx, c.db, query, []any{job}, func(rows *sql.Rows) (int, error) {
+		var n int
+		err := rows.Scan(&n)
+		return n, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return counts[0], nil
+}
+
+// interval returns how often leases are extended: a third of the ttl, but
+// at least a millisecond so tiny ttls do not spin.
+func (c *Coordinator) interval() time.Duration {
+	return max(c.ttl/3, time.Millisecond)
+}
+
+// exec1 runs a single-row UPDATE and reports whether a row was affected.
+func (c *Coordinator) exec1(ctx context.Context, query string, args ...any) (bool, error) {
+	res, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// LeaseWorker repeatedly leases ranges of a job from a Coordinator and pages
+// through them, checkpointing the cursor after every page.
+type LeaseWorker[T any] struct {
+	Coordinator *Coordinator
+	Job         string                   // Job whose ranges are processed
+	Owner       string                   // Unique worker identity, e.g. the pod name
+	Fetch       keyset.RangeFetchFunc[T] // Loads a page within a range
+	Cursor      keyset.CursorFunc[T]     // Encodes the cursor of an item
+	Page        keyset.Page              // Initial page of every range (Limit/Dir)
+	// PollInterval is how long to wait before retrying when every remaining
+	// range is leased by someone else. It defaults to a third of the lease ttl.
+	PollInterval time.Duration
+}
+
+// Run processes ranges until every range of the job is done or ctx is cancelled.
+// While a range is processed its lease is extended in the background; if the
+// lease is lost, the range is abandoned and Run moves on.
+func (w LeaseWorker[T]) Run(ctx context.Context, fn func(ctx context.Context, r keyset.Int64Range, batch []T) error) error {
+	poll := w.PollInterval
+	if poll <= 0 {
+		poll = w.Coordinator.interval()
+	}
+	for {
+		l, err := w.Coordinator.Acquire(ctx, w.Job, w.Owner)
+		if errors.Is(err, ErrNoLease) {
+			remaining, err := w.Coordinator.Remaining(ctx, w.Job)
+			if err != nil {
+				return err
+			}
+			if remaining == 0 {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(poll):
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := w.runLease(ctx, l, fn); err != nil && !errors.Is(err, ErrLeaseLost) {
+			return err
+		}
+	}
+}
+
+func (w LeaseWorker[T]) runLease(ctx context.Context, l *Lease, fn func(context.Context, keyset.Int64Range, []T) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Keep the lease alive between pages, e.g. while fn is slow.
+	go func() {
+		ticker := time.NewTicker(w.Coordinator.interval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.Coordinator.Heartbeat(ctx, &Lease{Job: l.Job, RangeID: l.RangeID, Owner: l.Owner}); err != nil {
+					cancel(err)
+					return
+				}
+			}
+		}
+	}()
+
+	stored, err := w.Coordinator.checkpoints.Load(ctx, l.CheckpointKey())
+	if err != nil {
+		return fmt.Errorf("ksql: load checkpoint %q: %w", l.CheckpointKey(), err)
+	}
+	p := w.Page.Resume(stored)
+	p.EnsureDefaults()
+
+	fetch := func(ctx context.Context, p keyset.Page) ([]T, error) {
+		return w.Fetch(ctx, l.Range, p)
+	}
+	for batch, err := range keyset.Pages(ctx, p, fetch, w.Cursor) {
+		if err != nil {
+			return leaseErr(ctx, err)
+		}
+		next := w.Cursor(keyset.BoundaryItem(p, batch))
+		if err := fn(ctx, l.Range, batch); err != nil {
+			return leaseErr(ctx, err)
+		}
+		if err := w.Coordinator.Commit(ctx, l, next); err != nil {
+			return leaseErr(ctx, err)
+		}
+	}
+	return w.Coordinator.Complete(ctx, l)
+}
+
+// leaseErr prefers the cancellation cause, so a lost lease is reported as
+// ErrLeaseLost rather than as a generic context error.
+func leaseErr(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
@@ -0,0 +1,219 @@
+package ksql_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func newCoordinator(t *testing.T, n int, ttl time.Duration) (*ksql.Coordinator, keyset.RangeFetchFunc[int64]) {
+	t.Helper()
+	ctx := context.Background()
+
+	db := openSQLite(t, n)
+	// SQLite allows a single writer; serialize access instead of retrying on SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	checkpoints := ksql.NewCheckpointTable(db, "", ksql.PlaceholderQuestion)
+	if err := checkpoints.CreateTable(ctx); err != nil {
+		t.Fatalf("create checkpoint table: %v", err)
+	}
+	c := ksql.NewCoordinator(db, "", ksql.PlaceholderQuestion, ttl, checkpoints)
+	if err := c.CreateTable(ctx); err != nil {
+		t.Fatalf("create lease table: %v", err)
+	}
+	fetch := ksql.FetchRangeByID(db, `SELECT id FROM posts`, keyset.Ascending, "id", ksql.PlaceholderQuestion, scanID)
+	return c, fetch
+}
+
+func TestCoordinator_PlanIsIdempotent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, _ := newCoordinator(t, 0, time.Minute)
+
+	ranges := keyset.SplitInt64(1, 100, 4)
+	for i := 0; i < 2; i++ {
+		if err := c.Plan(ctx, "job", ranges); err != nil {
+			t.Fatalf("plan #%d: %v", i, err)
+		}
+	}
+	if n, err := c.Remaining(ctx, "job"); err != nil || n != 4 {
+		t.Fatalf("want 4 remaining ranges, got (%d, %v)", n, err)
+	}
+
+	// A pod with a different split joins the stored plan instead of adding
+	// overlapping ranges.
+	if err := c.Plan(ctx, "job", keyset.SplitInt64(1, 120, 6)); err != nil {
+		t.Fatalf("plan with another split: %v", err)
+	}
+	if n, err := c.Remaining(ctx, "job"); err != nil || n != 4 {
+		t.Fatalf("want the stored 4 ranges, got (%d, %v)", n, err)
+	}
+	l, err := c.Acquire(ctx, "job", "pod-1")
+	if err != nil || l.Range != ranges[0] {
+		t.Fatalf("want the stored first range %v, got (%+v, %v)", ranges[0], l, err)
+	}
+}
+
+func TestCoordinator_AcquireHeartbeatComplete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, _ := newCoordinator(t, 0, time.Minute)
+
+	if err := c.Plan(ctx, "job", keyset.SplitInt64(1, 10, 1)); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	l, err := c.Acquire(ctx, "job", "a")
+	if err != nil || l.Range != (keyset.Int64Range{Min: 1, Max: 10}) {
+		t.Fatalf("acquire: %+v, %v", l, err)
+	}
+	if _, err := c.Acquire(ctx, "job", "b"); !errors.Is(err, ksql.ErrNoLease) {
+		t.Fatalf("live lease must not be handed out twice, got %v", err)
+	}
+	if err := c.Heartbeat(ctx, l); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if err := c.Complete(ctx, l); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if err := c.Heartbeat(ctx, l); !errors.Is(err, ksql.ErrLeaseLost) {
+		t.Fatalf("heartbeat after completion must fail, got %v", err)
+	}
+	if n, _ := c.Remaining(ctx, "job"); n != 0 {
+		t.Fatalf("want no remaining range, got %d", n)
+	}
+}
+
+func TestCoordinator_StealsExpiredLeaseAndResumes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, fetch := newCoordinator(t, 6, 20*time.Millisecond)
+
+	if err := c.Plan(ctx, "job", keyset.SplitInt64(1, 6, 1)); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	// Worker "a" processes one page, then dies without completing its lease.
+	stale, err := c.Acquire(ctx, "job", "a")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if err := c.Commit(ctx, stale, keyset.EncodeInt64Cursor(2)); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	var seen []int64
+	w := ksql.LeaseWorker[int64]{
+		Coordinator: c,
+		Job:         "job",
+		Owner:       "b",
+		Fetch:       fetch,
+		Cursor:      keyset.EncodeInt64Cursor,
+		Page:        keyset.Page{Limit: 2},
+	}
+	err = w.Run(ctx, func(_ context.Context, _ keyset.Int64Range, batch []int64) error {
+		seen = append(seen, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !slices.Equal(seen, []int64{3, 4, 5, 6}) {
+		t.Fatalf("want resume after checkpoint, got %v", seen)
+	}
+	if err := c.Heartbeat(ctx, stale); !errors.Is(err, ksql.ErrLeaseLost) {
+		t.Fatalf("stale owner must have lost the lease, got %v", err)
+	}
+}
+
+func TestLeaseWorker_ConcurrentWorkersCoverEveryRange(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, fetch := newCoordinator(t, 50, time.Second)
+
+	if err := c.Plan(ctx, "job", keyset.SplitInt64(1, 50, 5)); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	var (
+		mu   sync.Mutex
+		seen []int64
+		wg   sync.WaitGroup
+		errs = make(chan error, 3)
+	)
+	for _, owner := range []string{"pod-1", "pod-2", "pod-3"} {
+		w := ksql.LeaseWorker[int64]{
+			Coordinator:  c,
+			Job:          "job",
+			Owner:        owner,
+			Fetch:        fetch,
+			Cursor:       keyset.EncodeInt64Cursor,
+			Page:         keyset.Page{Limit: 4},
+			PollInterval: 5 * time.Millisecond,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- w.Run(ctx, func(_ context.Context, _ keyset.Int64Range, batch []int64) error {
+				mu.Lock()
+				defer mu.Unlock()
+				seen = append(seen, batch...)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+
+	slices.Sort(seen)
+	want := make([]int64, 0, 50)
+	for i := int64(1); i <= 50; i++ {
+		want = append(want, i)
+	}
+	if !slices.Equal(seen, want) {
+		t.Fatalf("every id must be processed exactly once, got %v", seen)
+	}
+}
+
+func TestCoordinator_TTL(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("a zero ttl must be rejected")
+			}
+		}()
+		ksql.NewCoordinator(nil, "", ksql.PlaceholderQuestion, 0, nil)
+	}()
+
+	// A ttl too small to divide into heartbeats must not panic or spin.
+	c, fetch := newCoordinator(t, 5, 2*time.Nanosecond)
+	if err := c.Plan(ctx, "job", keyset.SplitInt64(1, 5, 1)); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	w := ksql.LeaseWorker[int64]{Coordinator: c, Job: "job", Owner: "pod-1", Fetch: fetch, Cursor: keyset.EncodeInt64Cursor, Page: keyset.Page{Limit: 2}}
+	var n int
+	if err := w.Run(ctx, func(_ context.Context, _ keyset.Int64Range, batch []int64) error {
+		n += len(batch)
+		return nil
+	}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("want 5 ids, got %d", n)
+	}
+}
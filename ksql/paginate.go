@@ -0,0 +1,16 @@
+package ksql
+
+import (
+	"context"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Paginate runs a single page query and returns it with boundary cursors and
+// HasPrev/HasNext flags (see keyset.Paginate). Use keyset.DirFirst and
+// keyset.DirLast to jump to either end of the list:
+//
+//	res, err := ksql.Paginate(ctx, db, keyset.Page{Dir: keyset.DirLast, Limit: 20}, build, scanPost, postCursor)
+func Paginate[T any](ctx context.Context, db Querier, p keyset.Page, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) (keyset.Result[T], error) {
+	return keyset.Paginate(ctx, p, Fetch(db, build, scan), cursor)
+}
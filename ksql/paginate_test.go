@@ -0,0 +1,38 @@
+package ksql_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestPaginate_FirstAndLast(t *testing.T) {
+	t.Parallel()
+	db := openSQLite(t, 10)
+
+	build := func(p keyset.Page) (string, []any) {
+		return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderQuestion)
+	}
+
+	first, err := ksql.Paginate(context.Background(), db, keyset.Page{Dir: keyset.DirFirst, Limit: 3}, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int64{10, 9, 8}; !slices.Equal(first.Items, want) || first.HasPrev || !first.HasNext {
+		t.Fatalf("first page mismatch: %+v", first)
+	}
+
+	last, err := ksql.Paginate(context.Background(), db, keyset.Page{Dir: keyset.DirLast, Limit: 3}, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int64{3, 2, 1}; !slices.Equal(last.Items, want) || !last.HasPrev || last.HasNext {
+		t.Fatalf("last page mismatch: %+v", last)
+	}
+	if last.PrevCursor != keyset.EncodeInt64Cursor(3) {
+		t.Fatalf("prev cursor must continue from the first item: %+v", last)
+	}
+}
@@ -0,0 +1,15 @@
+package ksql
+
+import (
+	"context"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// Poll fetches up to limit items newer than head in base order (see keyset.Poll):
+//
+//	res, err := ksql.Poll(ctx, db, view.Head, 50, build, scanPost, postCursor)
+//	// "len(res.Items) new posts" (or "50+" when res.More)
+func Poll[T any](ctx context.Context, db Querier, head string, limit int, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) (keyset.PollResult[T], error) {
+	return keyset.Poll(ctx, head, limit, Fetch(db, build, scan), cursor)
+}
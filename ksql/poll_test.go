@@ -0,0 +1,42 @@
+package ksql_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestPoll_NewItemsSinceHead(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openSQLite(t, 10)
+
+	build := func(p keyset.Page) (string, []any) {
+		return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderQuestion)
+	}
+	view, err := ksql.Paginate(ctx, db, keyset.Page{Limit: 3}, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 11; i <= 15; i++ {
+		if _, err := db.ExecContext(ctx, `INSERT INTO posts (id, title) VALUES (?, ?)`, i, "new"); err != nil {
+			t.Fatalf("insert post: %v", err)
+		}
+	}
+
+	res, err := ksql.Poll(ctx, db, view.PrevCursor, 3, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Display order is DESC; the batch connects to the old head (10).
+	if want := []int64{13, 12, 11}; !slices.Equal(res.Items, want) || !res.More {
+		t.Fatalf("want %v with more waiting, got %+v", want, res)
+	}
+	if res.Head != keyset.EncodeInt64Cursor(13) {
+		t.Fatalf("head must advance to the newest item: %+v", res)
+	}
+}
@@ -0,0 +1,39 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// CountWindow returns a keyset.WindowCountFunc that runs the statement built
+// by build, typically a closure over CountByID, CountByTime or CountByTimeAndID.
+func CountWindow(db Querier, build BuildFunc) keyset.WindowCountFunc {
+	return func(ctx context.Context, p keyset.Page) (int64, error) {
+		query, args := build(p)
+		counts, err := Query(ctx, db, query, args, func(rows *sql.Rows) (int64, error) {
+			var n int64
+			err := rows.Scan(&n)
+			return n, err
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(counts) == 0 {
+			return 0, errors.New("ksql: count window: no rows")
+		}
+		return counts[0], nil
+	}
+}
+
+// Locate sets res.Position and res.Remaining (see keyset.Locate):
+//
+//	count := func(p keyset.Page) (string, []any) {
+//		return ksql.CountByID(`SELECT id FROM posts`, p, keyset.Descending, "id", ksql.PlaceholderDollar)
+//	}
+//	err := ksql.Locate(ctx, db, &res, count)
+func Locate[T any](ctx context.Context, db Querier, res *keyset.Result[T], count BuildFunc) error {
+	return keyset.Locate(ctx, res, CountWindow(db, count))
+}
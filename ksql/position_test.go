@@ -0,0 +1,60 @@
+package ksql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestCountByID_WindowWithoutLimit(t *testing.T) {
+	t.Parallel()
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(10), Dir: keyset.DirPrev, Limit: 3}
+	sql, args := ksql.CountByID(`SELECT id FROM posts WHERE published`, p, keyset.Descending, "id", ksql.PlaceholderDollar)
+
+	if sql != "SELECT COUNT(*) FROM (SELECT id FROM posts WHERE published AND id > $1) AS keyset_window" {
+		t.Fatalf("unexpected count query: %s", sql)
+	}
+	if len(args) != 1 || args[0] != int64(10) {
+		t.Fatalf("args mismatch: %v", args)
+	}
+}
+
+func TestCountByTimeAndID_Window(t *testing.T) {
+	t.Parallel()
+	p := keyset.Page{Cursor: keyset.EncodeTimePrefixCursor(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))}
+	sql, _ := ksql.CountByTimeAndID(`SELECT * FROM posts`, p, keyset.Descending, "created_at", "id", ksql.PlaceholderDollar)
+
+	if !strings.HasSuffix(sql, "WHERE created_at < $1) AS keyset_window") {
+		t.Fatalf("unexpected count query: %s", sql)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openSQLite(t, 250)
+
+	build := func(p keyset.Page) (string, []any) {
+		return ksql.QueryByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderQuestion)
+	}
+	count := func(p keyset.Page) (string, []any) {
+		return ksql.CountByID(`SELECT id FROM posts`, p, keyset.Ascending, "id", ksql.PlaceholderQuestion)
+	}
+
+	p := keyset.Page{Cursor: keyset.EncodeInt64Cursor(200), Limit: 25}
+	res, err := ksql.Paginate(ctx, db, p, build, scanID, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ksql.Locate(ctx, db, &res, count); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// showing 201-225, 25 more
+	if res.Position != 201 || res.Remaining != 25 {
+		t.Fatalf("want position 201 remaining 25, got %d/%d", res.Position, res.Remaining)
+	}
+}
@@ -0,0 +1,14 @@
+package ksql
+
+import (
+	"context"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// SnapshotCursor returns the cursor of the row with the largest key for use
+// as keyset.Page.Snapshot (see keyset.SnapshotCursor). ord is the base order
+// build is used with.
+func SnapshotCursor[T any](ctx context.Context, db Querier, ord keyset.Order, build BuildFunc, scan ScanFunc[T], cursor keyset.CursorFunc[T]) (string, error) {
+	return keyset.SnapshotCursor(ctx, ord, Fetch(db, build, scan), cursor)
+}
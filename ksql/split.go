@@ -0,0 +1,73 @@
+package ksql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// SplitByMinMax reads MIN(col) and MAX(col) over base and splits that
+// interval into n ranges of equal key width. base must select col.
+// It returns nil when base yields no rows.
+//
+// Equal-width ranges suit dense keys such as sequences; prefer SplitBySample
+// when keys are sparse or skewed.
+func SplitByMinMax(ctx context.Context, db Querier, base, col string, n int) ([]keyset.Int64Range, error) {
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM (%s) AS keyset_bounds", col, col, base)
+	bounds, err := Query(ctx, db, query, nil, func(rows *sql.Rows) ([2]sql.NullInt64, error) {
+		var b [2]sql.NullInt64
+		err := rows.Scan(&b[0], &b[1])
+		return b, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bounds) == 0 || !bounds[0][0].Valid || !bounds[0][1].Valid {
+		return nil, nil
+	}
+	return keyset.SplitInt64(bounds[0][0].Int64, bounds[0][1].Int64, n), nil
+}
+
+// SplitBySample runs sample, which must return a single integer key column
+// (e.g. `SELECT id FROM posts TABLESAMPLE SYSTEM (1)` on PostgreSQL), and
+// uses the quantiles of the returned keys as boundaries for n ranges.
+// See keyset.SplitInt64Samples.
+func SplitBySample(ctx context.Context, db Querier, sample string, args []any, n int) ([]keyset.Int64Range, error) {
+	keys, err := Query(ctx, db, sample, args, func(rows *sql.Rows) (int64, error) {
+		var k int64
+		err := rows.Scan(&k)
+		return k, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keyset.SplitInt64Samples(keys, n), nil
+}
+
+// WithinRange restricts base to rows whose integer key col lies in r.
+// The bounds are rendered as integer literals, so the result can be passed to
+// QueryByID and friends without shifting their placeholder numbering.
+func WithinRange(base, col string, r keyset.Int64Range) string {
+	return base + appendWhere(base, fmt.Sprintf("%s >= %d AND %s <= %d", col, r.Min, col, r.Max))
+}
+
+// FetchRangeByID returns a keyset.RangeFetchFunc that pages through a single
+// range with QueryByID, for use with keyset.Walker:
+//
+//	w := keyset.Walker[Post]{
+//		Fetch:   ksql.FetchRangeByID(db, `SELECT id, title FROM posts`, keyset.Ascending, "id", ksql.PlaceholderDollar, scanPost),
+//		Cursor:  func(p Post) string { return keyset.EncodeInt64Cursor(p.ID) },
+//		Page:    keyset.Page{Limit: 1000},
+//		Workers: 8,
+//	}
+//	err := w.Walk(ctx, ranges, handle)
+func FetchRangeByID[T any](db Querier, base string, ord keyset.Order, col string, ph Placeholder, scan ScanFunc[T]) keyset.RangeFetchFunc[T] {
+	return func(ctx context.Context, r keyset.Int64Range, p keyset.Page) ([]T, error) {
+		build := func(p keyset.Page) (string, []any) {
+			return QueryByID(WithinRange(base, col, r), p, ord, col, ph)
+		}
+		return Fetch(db, build, scan)(ctx, p)
+	}
+}
@@ -0,0 +1,108 @@
+package ksql_test
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/mickamy/go-keyset"
+	"github.com/mickamy/go-keyset/ksql"
+)
+
+func TestSplitByMinMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("splits bounds", func(t *testing.T) {
+		t.Parallel()
+		db, mock := newMock(t)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT MIN(id), MAX(id) FROM (SELECT id FROM posts) AS keyset_bounds`)).
+			WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(1, 100))
+
+		got, err := ksql.SplitByMinMax(context.Background(), db, `SELECT id FROM posts`, "id", 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []keyset.Int64Range{{Min: 1, Max: 50}, {Min: 51, Max: 100}}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		t.Parallel()
+		db, mock := newMock(t)
+		mock.ExpectQuery(`SELECT MIN\(id\), MAX\(id\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(nil, nil))
+
+		got, err := ksql.SplitByMinMax(context.Background(), db, `SELECT id FROM posts`, "id", 2)
+		if err != nil || got != nil {
+			t.Fatalf("want (nil, nil), got (%v, %v)", got, err)
+		}
+	})
+}
+
+func TestSplitBySample(t *testing.T) {
+	t.Parallel()
+	db, mock := newMock(t)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM posts TABLESAMPLE SYSTEM (1)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40).AddRow(10).AddRow(30).AddRow(20))
+
+	got, err := ksql.SplitBySample(context.Background(), db, `SELECT id FROM posts TABLESAMPLE SYSTEM (1)`, nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []keyset.Int64Range{{Min: math.MinInt64, Max: 29}, {Min: 30, Max: math.MaxInt64}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestWithinRange(t *testing.T) {
+	t.Parallel()
+
+	r := keyset.Int64Range{Min: 10, Max: 20}
+	if got := ksql.WithinRange(`SELECT id FROM posts`, "id", r); got != `SELECT id FROM posts WHERE id >= 10 AND id <= 20` {
+		t.Fatalf("unexpected WHERE: %s", got)
+	}
+	if got := ksql.WithinRange(`SELECT id FROM posts WHERE tenant_id = 1`, "id", r); got != `SELECT id FROM posts WHERE tenant_id = 1 AND id >= 10 AND id <= 20` {
+		t.Fatalf("unexpected AND: %s", got)
+	}
+}
+
+func TestFetchRangeByID_WithWalker(t *testing.T) {
+	t.Parallel()
+	db, mock := newMock(t)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM posts WHERE id >= 1 AND id <= 10 ORDER BY id ASC LIMIT $1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(7))
+
+	var (
+		mu   sync.Mutex
+		seen []int64
+	)
+	w := keyset.Walker[int64]{
+		Fetch:  ksql.FetchRangeByID(db, `SELECT id FROM posts`, keyset.Ascending, "id", ksql.PlaceholderDollar, scanID),
+		Cursor: keyset.EncodeInt64Cursor,
+		Page:   keyset.Page{Limit: 5},
+	}
+	err := w.Walk(context.Background(), []keyset.Int64Range{{Min: 1, Max: 10}}, func(_ context.Context, _ keyset.Int64Range, batch []int64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(seen, []int64{3, 7}) {
+		t.Fatalf("unexpected rows: %v", seen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -1,18 +1,44 @@
 package keyset
 
 import (
+	"errors"
 	"fmt"
 )
 
+// ErrInvalidPage is returned by Page.Validate for inconsistent pagination states.
+var ErrInvalidPage = errors.New("keyset: invalid page")
+
 // Dir represents the direction of pagination.
 // DirNext fetches the next page (after the cursor), DirPrev fetches the previous page (before the cursor).
+// DirFirst and DirLast jump to either end of the list and ignore the cursor.
 type Dir int
 
 const (
 	DirNext Dir = iota + 1
 	DirPrev
+	DirFirst
+	DirLast
 )
 
+// Backward reports whether pages in this direction are fetched in reverse
+// order (DirPrev and DirLast) and must be normalized for display.
+func (d Dir) Backward() bool {
+	return d == DirPrev || d == DirLast
+}
+
+// onward returns the direction that continues from a page fetched in d:
+// DirFirst continues with DirNext and DirLast with DirPrev.
+func (d Dir) onward() Dir {
+	switch d {
+	case DirFirst:
+		return DirNext
+	case DirLast:
+		return DirPrev
+	default:
+		return d
+	}
+}
+
 // Order represents sorting order (ascending or descending).
 type Order int
 
@@ -61,20 +87,117 @@ func (o Order) InequalityOp() string {
 	}
 }
 
+// BoundOp returns the comparison operator for a window boundary under this order.
+// It equals InequalityOp for Exclusive, and "<=" / ">=" for Inclusive.
+func (o Order) BoundOp(b Bound) string {
+	op := o.InequalityOp()
+	if b == Inclusive {
+		op += "="
+	}
+	return op
+}
+
+// Bound selects whether a window boundary includes the boundary row itself.
+type Bound int
+
+const (
+	Exclusive Bound = iota // Strict comparison (default)
+	Inclusive              // Also match the boundary row
+)
+
 // Page defines a keyset pagination state.
 type Page struct {
-	Cursor string // Encoded opaque cursor string
+	Cursor string // Encoded opaque cursor string, or a Token carrying Dir/Limit
 	Limit  int    // Number of items to fetch
 	Dir    Dir    // Direction of pagination
+
+	// Bound controls whether the Cursor row itself is part of the window.
+	// Inclusive re-fetches "the page starting at this item", e.g. to re-render
+	// the current page after edits using the cursor of its first item.
+	Bound Bound
+
+	// Until optionally stops the window at a second cursor of the same encoding,
+	// e.g. "items after A but before B". It lies beyond Cursor in the direction of
+	// pagination. UntilBound controls whether the Until row itself is included.
+	Until      string
+	UntilBound Bound
+
+	// Snapshot optionally pins the view to the rows that existed when browsing
+	// started: every window also requires key <= Snapshot, a cursor of the same
+	// encoding holding the max key at that time (see SnapshotCursor). It travels
+	// inside Tokens, so one browsing session keeps seeing a fixed view.
+	Snapshot string
 }
 
 // EnsureDefaults fills unset fields with default values.
+// A Token in Cursor (or Until) is unwrapped to its key cursor, and its
+// direction, limit and snapshot are used when Dir, Limit and Snapshot are unset.
+// For DirFirst and DirLast it clears Cursor and Bound, which do not apply.
 // It does not report invalid states; use Validate for strict checks.
 func (p *Page) EnsureDefaults() {
+	if t, err := DecodeToken(p.Cursor); err == nil {
+		p.Cursor = t.Cursor
+		if p.Dir == 0 {
+			p.Dir = t.Dir
+		}
+		if p.Limit <= 0 {
+			p.Limit = t.Limit
+		}
+		if p.Snapshot == "" {
+			p.Snapshot = t.Snapshot
+		}
+	}
+	if t, err := DecodeToken(p.Until); err == nil {
+		p.Until = t.Cursor
+	}
 	if p.Limit <= 0 {
 		p.Limit = 50
 	}
-	if p.Dir != DirNext && p.Dir != DirPrev {
+	switch p.Dir {
+	case DirNext, DirPrev:
+	case DirFirst, DirLast:
+		p.Cursor, p.Bound = "", Exclusive
+	default:
 		p.Dir = DirNext
 	}
 }
+
+// Resume returns p continuing from cursor, typically a stored checkpoint.
+// DirFirst and DirLast become DirNext and DirPrev, which take a cursor, so
+// EnsureDefaults does not drop it. An empty cursor leaves p unchanged.
+func (p Page) Resume(cursor string) Page {
+	if cursor != "" {
+		p.Cursor, p.Dir = cursor, p.Dir.onward()
+	}
+	return p
+}
+
+// Validate reports states that EnsureDefaults would silently repair or that
+// are likely mistakes, such as a DirPrev page without a cursor (use DirLast)
+// or a DirFirst/DirLast page with one, or a Token issued for a different
+// direction than Dir. The zero Dir and Limit are accepted.
+func (p Page) Validate() error {
+	if t, err := DecodeToken(p.Cursor); err == nil {
+		if p.Dir != 0 && p.Dir != t.Dir {
+			return fmt.Errorf("%w: cursor was issued for direction %d, not %d", ErrInvalidPage, t.Dir, p.Dir)
+		}
+		p.Cursor, p.Dir = t.Cursor, t.Dir
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrInvalidPage, p.Limit)
+	}
+	switch p.Dir {
+	case 0, DirNext:
+	case DirPrev:
+		if p.Cursor == "" {
+			return fmt.Errorf("%w: DirPrev requires a cursor; use DirLast for the last page", ErrInvalidPage)
+		}
+	case DirFirst, DirLast:
+		if p.Cursor != "" {
+			return fmt.Errorf("%w: DirFirst and DirLast do not take a cursor", ErrInvalidPage)
+		}
+	default:
+		return fmt.Errorf("%w: unknown direction %d", ErrInvalidPage, p.Dir)
+	}
+	return nil
+}
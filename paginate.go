@@ -0,0 +1,80 @@
+package keyset
+
+import (
+	"context"
+)
+
+// Paginate fetches the single page p and returns it with boundary cursors and
+// HasPrev/HasNext flags. One extra row is fetched to detect whether more items
+// exist in the direction of travel.
+//
+// The flags on the other side follow from the direction: a DirFirst page has
+// nothing before it and a DirLast page nothing after it. For DirNext/DirPrev
+// pages with a cursor, one more single-row fetch checks what lies behind the
+// cursor, since seek cursors (e.g. EncodeTimePrefixCursor) and cursors of
+// deleted rows need not have a row there.
+//
+// Jump to the newest item of an ascending list:
+//
+//	res, err := keyset.Paginate(ctx, keyset.Page{Dir: keyset.DirLast, Limit: 20}, fetch, cursor)
+func Paginate[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T]) (Result[T], error) {
+	return paginate(ctx, p, fetch, cursor, true)
+}
+
+// paginate implements Paginate. Without probeBehind, the flag behind the
+// cursor is only assumed from its presence, for callers that ignore it.
+func paginate[T any](ctx context.Context, p Page, fetch FetchFunc[T], cursor CursorFunc[T], probeBehind bool) (Result[T], error) {
+	p.EnsureDefaults()
+	probe := p
+	probe.Limit++
+	items, err := fetch(ctx, probe)
+	if err != nil {
+		return Result[T]{}, err
+	}
+
+	more := len(items) > p.Limit
+	if more {
+		// Drop the probe row, which lies beyond the page in the direction of travel.
+		if p.Dir.Backward() {
+			items = items[len(items)-p.Limit:]
+		} else {
+			items = items[:p.Limit]
+		}
+	}
+
+	behind := p.Cursor != ""
+	if behind && probeBehind {
+		if behind, err = rowBehind(ctx, p, fetch); err != nil {
+			return Result[T]{}, err
+		}
+	}
+
+	r := newResult(items, cursor)
+	r.Snapshot = p.Snapshot
+	switch p.Dir {
+	case DirFirst:
+		r.HasNext = more
+	case DirLast:
+		r.HasPrev = more
+	case DirNext:
+		r.HasPrev, r.HasNext = behind, more
+	case DirPrev:
+		r.HasPrev, r.HasNext = more, behind
+	}
+	return r, nil
+}
+
+// rowBehind reports whether any row outside the window of p lies behind its
+// cursor, opposite to its direction of travel. That includes the cursor row
+// itself unless p is Inclusive.
+func rowBehind[T any](ctx context.Context, p Page, fetch FetchFunc[T]) (bool, error) {
+	back := Page{Cursor: p.Cursor, Limit: 1, Dir: DirPrev, Snapshot: p.Snapshot}
+	if p.Bound == Exclusive {
+		back.Bound = Inclusive
+	}
+	if p.Dir == DirPrev {
+		back.Dir = DirNext
+	}
+	items, err := fetch(ctx, back)
+	return len(items) > 0, err
+}
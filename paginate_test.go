@@ -0,0 +1,151 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7}
+	cur := keyset.EncodeInt64Cursor
+	cases := []struct {
+		name             string
+		page             keyset.Page
+		want             []int64
+		hasPrev, hasNext bool
+	}{
+		{"first", keyset.Page{Dir: keyset.DirFirst, Limit: 3}, []int64{1, 2, 3}, false, true},
+		{"last", keyset.Page{Dir: keyset.DirLast, Limit: 3}, []int64{5, 6, 7}, true, false},
+		{"last ignores cursor", keyset.Page{Dir: keyset.DirLast, Cursor: cur(2), Limit: 3}, []int64{5, 6, 7}, true, false},
+		{"last fits", keyset.Page{Dir: keyset.DirLast, Limit: 7}, ids, false, false},
+		{"next", keyset.Page{Cursor: cur(3), Limit: 3}, []int64{4, 5, 6}, true, true},
+		{"next to end", keyset.Page{Cursor: cur(4), Limit: 3}, []int64{5, 6, 7}, true, false},
+		{"prev", keyset.Page{Cursor: cur(5), Dir: keyset.DirPrev, Limit: 3}, []int64{2, 3, 4}, true, true},
+		{"prev to start", keyset.Page{Cursor: cur(4), Dir: keyset.DirPrev, Limit: 3}, []int64{1, 2, 3}, false, true},
+		{"inclusive", keyset.Page{Cursor: cur(3), Bound: keyset.Inclusive, Limit: 3}, []int64{3, 4, 5}, true, true},
+		{"inclusive at start", keyset.Page{Cursor: cur(1), Bound: keyset.Inclusive, Limit: 3}, []int64{1, 2, 3}, false, true},
+		{"seek before start", keyset.Page{Cursor: cur(0), Limit: 3}, []int64{1, 2, 3}, false, true},
+		{"deleted cursor row at end", keyset.Page{Cursor: cur(8), Dir: keyset.DirPrev, Limit: 3}, []int64{5, 6, 7}, true, false},
+		{"inclusive prev at end", keyset.Page{Cursor: cur(7), Dir: keyset.DirPrev, Bound: keyset.Inclusive, Limit: 3}, []int64{5, 6, 7}, true, false},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			res, err := keyset.Paginate(context.Background(), c.page, memFetch(ids, &calls), cur)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(res.Items, c.want) {
+				t.Fatalf("items want %v, got %v", c.want, res.Items)
+			}
+			if res.HasPrev != c.hasPrev || res.HasNext != c.hasNext {
+				t.Fatalf("flags want prev=%v next=%v, got prev=%v next=%v", c.hasPrev, c.hasNext, res.HasPrev, res.HasNext)
+			}
+			if res.PrevCursor != cur(c.want[0]) || res.NextCursor != cur(c.want[len(c.want)-1]) {
+				t.Fatalf("cursors must point at both ends: %+v", res)
+			}
+		})
+	}
+}
+
+func TestPaginate_SeekWithPrefixCursor(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	keys := []string{
+		keyset.EncodeTimeAndInt64Cursor(day(10), 1),
+		keyset.EncodeTimeAndInt64Cursor(day(11), 2),
+		keyset.EncodeTimeAndInt64Cursor(day(12), 3),
+	}
+	// fetch serves keys in order; items are their own cursors.
+	fetch := func(_ context.Context, p keyset.Page) ([]string, error) {
+		var window []string
+		for i := range keys {
+			k := keys[i]
+			if p.Dir.Backward() {
+				k = keys[len(keys)-1-i]
+			}
+			c, _ := keyset.CompareCursors(k, p.Cursor)
+			if p.Dir.Backward() {
+				c = -c
+			}
+			if (c > 0 || (c == 0 && p.Bound == keyset.Inclusive)) && len(window) < p.Limit {
+				window = append(window, k)
+			}
+		}
+		return keyset.NormalizePageResult(p, window), nil
+	}
+	same := func(s string) string { return s }
+
+	res, err := keyset.Paginate(context.Background(), keyset.Page{Cursor: keyset.EncodeTimePrefixCursor(day(1)), Limit: 2}, fetch, same)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(res.Items, keys[:2]) || res.HasPrev || !res.HasNext {
+		t.Fatalf("seek before the first item must not have a previous page: %+v", res)
+	}
+
+	res, err = keyset.Paginate(context.Background(), keyset.Page{Cursor: keyset.EncodeTimePrefixCursor(day(11).Add(time.Hour)), Limit: 2}, fetch, same)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(res.Items, keys[2:]) || !res.HasPrev || res.HasNext {
+		t.Fatalf("seek between items must have a previous page only: %+v", res)
+	}
+}
+
+func TestPages_DirLastContinuesBackward(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var got [][]int64
+	for batch, err := range keyset.Pages(context.Background(), keyset.Page{Dir: keyset.DirLast, Limit: 2}, memFetch([]int64{1, 2, 3, 4, 5}, &calls), keyset.EncodeInt64Cursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, batch)
+	}
+
+	want := [][]int64{{4, 5}, {2, 3}, {1}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]int64]) {
+		t.Fatalf("pages mismatch: want %v, got %v", want, got)
+	}
+}
+
+func TestPage_Validate(t *testing.T) {
+	t.Parallel()
+
+	cur := keyset.EncodeInt64Cursor(1)
+	valid := []keyset.Page{
+		{},
+		{Cursor: cur, Dir: keyset.DirPrev},
+		{Dir: keyset.DirFirst, Limit: 10},
+		{Dir: keyset.DirLast},
+	}
+	for _, p := range valid {
+		if err := p.Validate(); err != nil {
+			t.Fatalf("want %+v valid, got %v", p, err)
+		}
+	}
+
+	invalid := []keyset.Page{
+		{Limit: -1},
+		{Dir: keyset.DirPrev},
+		{Dir: keyset.DirLast, Cursor: cur},
+		{Dir: keyset.Dir(42)},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); !errors.Is(err, keyset.ErrInvalidPage) {
+			t.Fatalf("want ErrInvalidPage for %+v, got %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package keyset
+
+import (
+	"context"
+)
+
+// PollResult holds the items that appeared before the head of a list.
+type PollResult[T any] struct {
+	Items []T    // New items in display order, adjacent to the old head
+	Head  string // Cursor of the newest item seen; the old head when nothing is new
+	More  bool   // Whether more new items are waiting beyond Items
+}
+
+// Poll fetches up to limit items that precede head, the cursor of the first
+// item of the current view, in base order; for a created_at DESC feed those
+// are the posts that arrived since the view was loaded ("5 new posts").
+//
+// It reads the DirPrev window of head, so the returned items connect to the
+// current view without a gap. When More is set, poll again from the returned
+// Head to load the next batch. An empty head polls from the end of the list.
+func Poll[T any](ctx context.Context, head string, limit int, fetch FetchFunc[T], cursor CursorFunc[T]) (PollResult[T], error) {
+	p := Page{Cursor: head, Limit: limit, Dir: DirPrev}
+	if head == "" {
+		p.Dir = DirLast
+	}
+	r, err := paginate(ctx, p, fetch, cursor, false)
+	if err != nil {
+		return PollResult[T]{}, err
+	}
+	out := PollResult[T]{Items: r.Items, Head: head, More: r.HasPrev}
+	if len(r.Items) > 0 {
+		out.Head = r.PrevCursor
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package keyset_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// memFetch serves ids ascending, so "newer than head" lies before it:
+	// the view starts at head 4 and 1..3 arrived since.
+	ids := []int64{1, 2, 3, 4, 5, 6}
+	calls := 0
+	head := keyset.EncodeInt64Cursor(4)
+
+	res, err := keyset.Poll(ctx, head, 2, memFetch(ids, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(res.Items, []int64{2, 3}) || !res.More || res.Head != keyset.EncodeInt64Cursor(2) {
+		t.Fatalf("first poll mismatch: %+v", res)
+	}
+
+	res, err = keyset.Poll(ctx, res.Head, 2, memFetch(ids, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(res.Items, []int64{1}) || res.More || res.Head != keyset.EncodeInt64Cursor(1) {
+		t.Fatalf("second poll mismatch: %+v", res)
+	}
+
+	res, err = keyset.Poll(ctx, res.Head, 2, memFetch(ids, &calls), keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Items) != 0 || res.More || res.Head != keyset.EncodeInt64Cursor(1) {
+		t.Fatalf("idle poll must keep the head: %+v", res)
+	}
+}
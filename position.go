@@ -0,0 +1,39 @@
+package keyset
+
+import (
+	"context"
+)
+
+// WindowCountFunc counts the rows in the window of p, ignoring p.Limit.
+// Adapters provide it on top of their window builders (e.g. ksql.CountByID).
+type WindowCountFunc func(ctx context.Context, p Page) (int64, error)
+
+// Locate sets r.Position and r.Remaining ("showing 201-250, 1,200 more") by
+// counting the rows before the first item (the DirPrev window of PrevCursor)
+// and after the last one (the DirNext window of NextCursor). A count is
+// skipped when HasPrev or HasNext already says there is nothing to count.
+//
+// Both counts scan the rows they count, so they cost about as much as an
+// OFFSET query at the same position; keep them for UIs that need the numbers.
+func Locate[T any](ctx context.Context, r *Result[T], count WindowCountFunc) error {
+	if len(r.Items) == 0 {
+		return nil
+	}
+	var before, after int64
+	if r.HasPrev {
+		n, err := count(ctx, Page{Cursor: r.PrevCursor, Dir: DirPrev, Snapshot: r.Snapshot})
+		if err != nil {
+			return err
+		}
+		before = n
+	}
+	if r.HasNext {
+		n, err := count(ctx, Page{Cursor: r.NextCursor, Dir: DirNext, Snapshot: r.Snapshot})
+		if err != nil {
+			return err
+		}
+		after = n
+	}
+	r.Position, r.Remaining = before+1, after
+	return nil
+}
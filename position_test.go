@@ -0,0 +1,55 @@
+package keyset_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// memCount counts the window of p over ids (sorted ascending), ignoring the limit.
+func memCount(ids []int64, calls *int) keyset.WindowCountFunc {
+	return func(ctx context.Context, p keyset.Page) (int64, error) {
+		*calls++
+		p.Limit = len(ids)
+		items, err := memFetch(ids, new(int))(ctx, p)
+		return int64(len(items)), err
+	}
+}
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	cases := []struct {
+		name                string
+		page                keyset.Page
+		position, remaining int64
+		calls               int
+	}{
+		{"first", keyset.Page{Limit: 3}, 1, 7, 1},
+		{"middle", keyset.Page{Cursor: keyset.EncodeInt64Cursor(4), Limit: 3}, 5, 3, 2},
+		{"last", keyset.Page{Dir: keyset.DirLast, Limit: 3}, 8, 0, 1},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			fetches, counts := 0, 0
+			res, err := keyset.Paginate(ctx, c.page, memFetch(ids, &fetches), keyset.EncodeInt64Cursor)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := keyset.Locate(ctx, &res, memCount(ids, &counts)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.Position != c.position || res.Remaining != c.remaining {
+				t.Fatalf("want position=%d remaining=%d, got %d/%d", c.position, c.remaining, res.Position, res.Remaining)
+			}
+			if counts != c.calls {
+				t.Fatalf("count calls want %d, got %d", c.calls, counts)
+			}
+		})
+	}
+}
@@ -5,10 +5,55 @@ import (
 )
 
 // NormalizePageResult reverses the given result slice in-place
-// if the page direction is DirPrev. It returns the same slice for fluency.
+// if the page direction is DirPrev or DirLast (including a direction carried
+// by a Token cursor). It returns the same slice for fluency.
 func NormalizePageResult[T any](p Page, results []T) []T {
-	if p.Dir == DirPrev {
+	p.EnsureDefaults()
+	if p.Dir.Backward() {
 		slices.Reverse(results)
 	}
 	return results
 }
+
+// Result is a page of items in display order together with the cursors
+// needed to continue in either direction.
+type Result[T any] struct {
+	Items      []T
+	PrevCursor string // Cursor of the first item; "" when Items is empty
+	NextCursor string // Cursor of the last item; "" when Items is empty
+	HasPrev    bool   // Whether more items exist before the first item
+	HasNext    bool   // Whether more items exist after the last item
+	Total      *Total // Row count of the whole list; nil unless requested
+	Position   int64  // 1-based index of the first item in the list; 0 unless located (see Locate)
+	Remaining  int64  // Number of items after the last item; valid when Position > 0
+	Snapshot   string // Page.Snapshot the items were read under, carried by the tokens
+}
+
+// NextToken returns NextCursor as a Token that continues with DirNext,
+// so clients need not send the direction. A positive limit is embedded too.
+// It returns "" when there is no next page.
+func (r Result[T]) NextToken(limit int) string {
+	if !r.HasNext {
+		return ""
+	}
+	return Token{Cursor: r.NextCursor, Dir: DirNext, Limit: limit, Snapshot: r.Snapshot}.Encode()
+}
+
+// PrevToken returns PrevCursor as a Token that continues with DirPrev.
+// It returns "" when there is no previous page.
+func (r Result[T]) PrevToken(limit int) string {
+	if !r.HasPrev {
+		return ""
+	}
+	return Token{Cursor: r.PrevCursor, Dir: DirPrev, Limit: limit, Snapshot: r.Snapshot}.Encode()
+}
+
+// newResult fills the boundary cursors of items, which must be in display order.
+func newResult[T any](items []T, cursor CursorFunc[T]) Result[T] {
+	r := Result[T]{Items: items}
+	if n := len(items); n > 0 {
+		r.PrevCursor = cursor(items[0])
+		r.NextCursor = cursor(items[n-1])
+	}
+	return r
+}
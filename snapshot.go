@@ -0,0 +1,24 @@
+package keyset
+
+import (
+	"context"
+)
+
+// SnapshotCursor returns the cursor of the item with the largest key, for use
+// as Page.Snapshot on the first page of a browsing session. ord is the base
+// order fetch pages in; the largest key is at the start of a Descending list
+// and at the end of an Ascending one. It returns "" for an empty list.
+//
+// Keys are assumed to grow as rows are inserted (sequences, creation times),
+// so the snapshot excludes rows that arrive later.
+func SnapshotCursor[T any](ctx context.Context, ord Order, fetch FetchFunc[T], cursor CursorFunc[T]) (string, error) {
+	p := Page{Dir: DirFirst, Limit: 1}
+	if ord == Ascending {
+		p.Dir = DirLast
+	}
+	items, err := fetch(ctx, p)
+	if err != nil || len(items) == 0 {
+		return "", err
+	}
+	return cursor(items[0]), nil
+}
@@ -0,0 +1,60 @@
+package keyset_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestToken_CarriesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	want := keyset.Token{Cursor: keyset.EncodeInt64Cursor(5), Dir: keyset.DirPrev, Limit: 10, Snapshot: keyset.EncodeInt64Cursor(99)}
+	got, err := keyset.DecodeToken(want.Encode())
+	if err != nil || got != want {
+		t.Fatalf("round trip mismatch: want %+v, got (%+v, %v)", want, got, err)
+	}
+
+	p := keyset.Page{Cursor: want.Encode()}
+	p.EnsureDefaults()
+	if p.Snapshot != want.Snapshot {
+		t.Fatalf("snapshot not applied: %+v", p)
+	}
+}
+
+func TestSnapshot_FixedViewAcrossPages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ids := []int64{1, 2, 3, 4, 5, 6}
+	calls := 0
+	fetch := func(ctx context.Context, p keyset.Page) ([]int64, error) {
+		return memFetch(ids, &calls)(ctx, p)
+	}
+
+	snap, err := keyset.SnapshotCursor(ctx, keyset.Ascending, fetch, keyset.EncodeInt64Cursor)
+	if err != nil || snap != keyset.EncodeInt64Cursor(6) {
+		t.Fatalf("want snapshot at 6, got (%q, %v)", snap, err)
+	}
+	last, err := keyset.Paginate(ctx, keyset.Page{Dir: keyset.DirLast, Limit: 2, Snapshot: snap}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// New rows arrive while the user pages back and forth.
+	ids = append(ids, 7, 8, 9)
+
+	prev, err := keyset.Paginate(ctx, keyset.Page{Cursor: last.PrevToken(0)}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next, err := keyset.Paginate(ctx, keyset.Page{Cursor: prev.NextToken(0)}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(next.Items, []int64{5, 6}) || next.HasNext {
+		t.Fatalf("pinned view must end at the snapshot, got %+v", next)
+	}
+}
@@ -0,0 +1,74 @@
+package keyset
+
+import (
+	"math"
+	"slices"
+)
+
+// Int64Range is an inclusive range [Min, Max] of an integer key,
+// used to partition a table for parallel scanning.
+type Int64Range struct {
+	Min int64
+	Max int64
+}
+
+// Contains reports whether v falls within the range.
+func (r Int64Range) Contains(v int64) bool {
+	return r.Min <= v && v <= r.Max
+}
+
+// SplitInt64 divides [lo, hi] into at most n contiguous ranges of (nearly)
+// equal width. It returns fewer ranges when the interval holds fewer than n
+// keys and nil when hi < lo. n < 1 is treated as 1.
+func SplitInt64(lo, hi int64, n int) []Int64Range {
+	if hi < lo {
+		return nil
+	}
+	if n < 1 {
+		n = 1
+	}
+	// Work in uint64 so that spans covering most of the int64 space don't overflow.
+	span := uint64(hi) - uint64(lo) // number of keys minus one
+	if span < uint64(n-1) {
+		n = int(span) + 1
+	}
+	q, r := span/uint64(n), span%uint64(n)
+
+	ranges := make([]Int64Range, 0, n)
+	start := uint64(lo)
+	for i := 0; i < n; i++ {
+		size := q
+		if uint64(i) <= r {
+			size++
+		}
+		end := start + size - 1
+		ranges = append(ranges, Int64Range{Min: int64(start), Max: int64(end)})
+		start = end + 1
+	}
+	return ranges
+}
+
+// SplitInt64Samples derives at most n ranges from a sample of key values by
+// using its quantiles as boundaries. The first and last ranges are open-ended
+// (down to math.MinInt64 and up to math.MaxInt64) because a sample rarely
+// contains the true extremes. It returns a single full range for an empty sample.
+func SplitInt64Samples(samples []int64, n int) []Int64Range {
+	if n < 1 {
+		n = 1
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	ranges := make([]Int64Range, 0, n)
+	start := int64(math.MinInt64)
+	for i := 1; i < n && len(sorted) > 0; i++ {
+		b := sorted[len(sorted)*i/n]
+		if b <= start {
+			// Duplicate or skewed samples: skip empty ranges.
+			continue
+		}
+		ranges = append(ranges, Int64Range{Min: start, Max: b - 1})
+		start = b
+	}
+	return append(ranges, Int64Range{Min: start, Max: math.MaxInt64})
+}
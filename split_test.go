@@ -0,0 +1,73 @@
+package keyset_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestSplitInt64(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		lo, hi int64
+		n      int
+		want   []keyset.Int64Range
+	}{
+		{"even", 1, 100, 4, []keyset.Int64Range{{1, 25}, {26, 50}, {51, 75}, {76, 100}}},
+		{"remainder goes to leading ranges", 1, 10, 3, []keyset.Int64Range{{1, 4}, {5, 7}, {8, 10}}},
+		{"fewer keys than ranges", 5, 6, 4, []keyset.Int64Range{{5, 5}, {6, 6}}},
+		{"single range", -3, 3, 0, []keyset.Int64Range{{-3, 3}}},
+		{"empty", 2, 1, 3, nil},
+		{"full int64 space", math.MinInt64, math.MaxInt64, 2, []keyset.Int64Range{{math.MinInt64, -1}, {0, math.MaxInt64}}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			got := keyset.SplitInt64(c.lo, c.hi, c.n)
+			if !slices.Equal(got, c.want) {
+				t.Fatalf("want %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitInt64Samples(t *testing.T) {
+	t.Parallel()
+
+	t.Run("quantile boundaries", func(t *testing.T) {
+		t.Parallel()
+		got := keyset.SplitInt64Samples([]int64{70, 10, 40, 20, 80, 50, 30, 60}, 4)
+		want := []keyset.Int64Range{
+			{math.MinInt64, 29},
+			{30, 49},
+			{50, 69},
+			{70, math.MaxInt64},
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("duplicate samples collapse", func(t *testing.T) {
+		t.Parallel()
+		got := keyset.SplitInt64Samples([]int64{5, 5, 5, 5}, 4)
+		want := []keyset.Int64Range{{math.MinInt64, 4}, {5, math.MaxInt64}}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("empty sample", func(t *testing.T) {
+		t.Parallel()
+		got := keyset.SplitInt64Samples(nil, 4)
+		want := []keyset.Int64Range{{math.MinInt64, math.MaxInt64}}
+		if !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+}
@@ -2,15 +2,23 @@ package keyset
 
 import "strings"
 
-// EffectiveOrder returns ord if DirNext or the reversed order if DirPrev.
+// EffectiveOrder returns ord if DirNext or DirFirst, or the reversed order if
+// DirPrev or DirLast (see Dir.Backward).
 // This is a common utility across adapters to compute the ORDER used for the SQL window.
 func EffectiveOrder(ord Order, dir Dir) Order {
-	if dir == DirPrev {
+	if dir.Backward() {
 		return ord.Reverse()
 	}
 	return ord
 }
 
+// UntilOrder returns the order whose comparison operators select the rows
+// on the near side of Page.Until, i.e. the reverse of the effective order.
+// Adapters combine it with Page.UntilBound (see Order.BoundOp).
+func UntilOrder(ord Order, dir Dir) Order {
+	return EffectiveOrder(ord, dir).Reverse()
+}
+
 // StableWhereTimeAndID builds the stable composite key condition for keyset windows.
 //
 // For Descending order it returns the SQL fragment:
@@ -24,11 +32,24 @@ func EffectiveOrder(ord Order, dir Dir) Order {
 // The placeholders are intended to be bound with (t, t, id) in that order.
 // The function only composes the SQL fragment and is ORM-agnostic.
 func StableWhereTimeAndID(timeCol, idCol string, ord Order) string {
-	var tCmp, idCmp string
+	return StableWhereTimeAndIDBound(timeCol, idCol, ord, Exclusive)
+}
+
+// StableWhereTimeAndIDBound is like StableWhereTimeAndID, but with an Inclusive
+// bound the id comparison also matches the boundary row itself:
+//
+//	(timeCol < ?) OR (timeCol = ? AND idCol <= ?)
+//
+// The time comparison stays strict; rows sharing the boundary time are
+// decided by the id comparison alone.
+func StableWhereTimeAndIDBound(timeCol, idCol string, ord Order, bound Bound) string {
+	tCmp := ">"
 	if ord == Descending {
-		tCmp, idCmp = "<", "<"
-	} else {
-		tCmp, idCmp = ">", ">"
+		tCmp = "<"
+	}
+	idCmp := tCmp
+	if bound == Inclusive {
+		idCmp += "="
 	}
 	var b strings.Builder
 	b.WriteString("(")
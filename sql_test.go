@@ -39,3 +39,43 @@ func TestOrderClause(t *testing.T) {
 		t.Fatalf("unexpected ORDER clause: want %q got %q", want, got)
 	}
 }
+
+func TestStableWhereTimeAndIDBound(t *testing.T) {
+	t.Parallel()
+
+	got := keyset.StableWhereTimeAndIDBound("created_at", "id", keyset.Ascending, keyset.Inclusive)
+	if got != "(created_at > ?) OR (created_at = ? AND id >= ?)" {
+		t.Fatalf("unexpected inclusive ASC where: %s", got)
+	}
+	got = keyset.StableWhereTimeAndIDBound("created_at", "id", keyset.Descending, keyset.Exclusive)
+	if got != keyset.StableWhereTimeAndID("created_at", "id", keyset.Descending) {
+		t.Fatalf("exclusive bound must match StableWhereTimeAndID: %s", got)
+	}
+}
+
+func TestUntilOrder(t *testing.T) {
+	t.Parallel()
+
+	if keyset.UntilOrder(keyset.Descending, keyset.DirNext) != keyset.Ascending {
+		t.Fatalf("DirNext should reverse the base order for Until")
+	}
+	if keyset.UntilOrder(keyset.Descending, keyset.DirPrev) != keyset.Descending {
+		t.Fatalf("DirPrev should keep the base order for Until")
+	}
+	if op := keyset.Ascending.BoundOp(keyset.Inclusive); op != ">=" {
+		t.Fatalf("unexpected inclusive op: %s", op)
+	}
+	if op := keyset.Descending.BoundOp(keyset.Exclusive); op != "<" {
+		t.Fatalf("unexpected exclusive op: %s", op)
+	}
+}
+
+func TestEffectiveOrder_FirstLast(t *testing.T) {
+	t.Parallel()
+	if o := keyset.EffectiveOrder(keyset.Descending, keyset.DirFirst); o != keyset.Descending {
+		t.Fatalf("DirFirst must keep the base order, got %v", o)
+	}
+	if o := keyset.EffectiveOrder(keyset.Descending, keyset.DirLast); o != keyset.Ascending {
+		t.Fatalf("DirLast must reverse the base order, got %v", o)
+	}
+}
@@ -0,0 +1,81 @@
+package keyset
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Tombstone records a row deleted since a sync token.
+type Tombstone struct {
+	ID        int64
+	DeletedAt time.Time
+}
+
+// SyncResult is one batch of an incremental sync.
+type SyncResult[T any] struct {
+	Upserts    []T         // Rows created or updated since the token, in (changed_at, id) order
+	Tombstones []Tombstone // Rows deleted since the token
+	Token      string      // Sync token to send with the next request
+	CaughtUp   bool        // Whether no further changes are pending after Token
+}
+
+// Syncer serves "everything changed since token X" to offline-first clients,
+// including deletions recorded in a deleted_at column (soft deletes).
+//
+// Fetch must page in ascending (changed_at, id) order over all rows,
+// soft-deleted ones included, where changed_at is the time of the last
+// change: COALESCE(deleted_at, updated_at) when deletions don't bump
+// updated_at. Key returns that time and the id of an item.
+type Syncer[T any] struct {
+	Fetch   FetchFunc[T]
+	Key     func(item T) (time.Time, int64)
+	Deleted func(item T) bool // Reports whether an item is a tombstone
+
+	Limit int              // Changes per batch
+	Lag   time.Duration    // Changes newer than now-Lag wait for a later sync (see Tailer)
+	Now   func() time.Time // Defaults to time.Now
+}
+
+// Sync returns the changes after token ("" for a full sync). Clients apply
+// upserts and tombstones, store the returned Token, and repeat until CaughtUp.
+func (s Syncer[T]) Sync(ctx context.Context, token string) (SyncResult[T], error) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	p := Page{
+		Cursor:     token,
+		Limit:      s.Limit,
+		Dir:        DirNext,
+		Until:      EncodeTimePrefixCursor(now().Add(-s.Lag)),
+		UntilBound: Inclusive,
+	}
+	p.EnsureDefaults()
+	if p.Cursor != "" {
+		if _, _, err := DecodeTimeAndInt64Cursor(p.Cursor); err != nil {
+			return SyncResult[T]{}, fmt.Errorf("keyset: invalid sync token: %w", err)
+		}
+	}
+
+	r, err := paginate(ctx, p, s.Fetch, func(item T) string {
+		return EncodeTimeAndInt64Cursor(s.Key(item))
+	}, false)
+	if err != nil {
+		return SyncResult[T]{}, err
+	}
+
+	out := SyncResult[T]{Token: NextToken(p.Cursor, 0), CaughtUp: !r.HasNext}
+	for _, item := range r.Items {
+		if s.Deleted(item) {
+			at, id := s.Key(item)
+			out.Tombstones = append(out.Tombstones, Tombstone{ID: id, DeletedAt: at})
+		} else {
+			out.Upserts = append(out.Upserts, item)
+		}
+	}
+	if r.NextCursor != "" {
+		out.Token = NextToken(r.NextCursor, 0)
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package keyset_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestSyncer_UpsertsTombstonesAndCatchUp(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	table := &changeTable{
+		rows:    map[int64]time.Time{1: at(1), 2: at(2), 3: at(3)},
+		deleted: map[int64]bool{},
+	}
+	s := keyset.Syncer[change]{
+		Fetch:   table.fetch,
+		Key:     func(ch change) (time.Time, int64) { return ch.UpdatedAt, ch.ID },
+		Deleted: func(ch change) bool { return ch.Deleted },
+		Limit:   2,
+		Now:     func() time.Time { return at(100) },
+	}
+	ids := func(items []change) []int64 {
+		var out []int64
+		for _, ch := range items {
+			out = append(out, ch.ID)
+		}
+		return out
+	}
+
+	// Full sync in two batches.
+	res, err := s.Sync(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids(res.Upserts), []int64{1, 2}) || res.CaughtUp || res.Token == "" {
+		t.Fatalf("first batch mismatch: %+v", res)
+	}
+	res, err = s.Sync(ctx, res.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids(res.Upserts), []int64{3}) || !res.CaughtUp {
+		t.Fatalf("second batch mismatch: %+v", res)
+	}
+	token := res.Token
+
+	// Nothing changed: same token, caught up.
+	res, err = s.Sync(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Upserts)+len(res.Tombstones) != 0 || !res.CaughtUp || res.Token != token {
+		t.Fatalf("idle sync mismatch: %+v", res)
+	}
+
+	// Row 2 is soft-deleted, row 4 created.
+	table.put(2, at(10))
+	table.deleted[2] = true
+	table.put(4, at(11))
+	res, err = s.Sync(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids(res.Upserts), []int64{4}) || !res.CaughtUp {
+		t.Fatalf("upserts mismatch: %+v", res)
+	}
+	if want := []keyset.Tombstone{{ID: 2, DeletedAt: at(10)}}; !slices.Equal(res.Tombstones, want) {
+		t.Fatalf("tombstones mismatch: %+v", res.Tombstones)
+	}
+
+	if _, err := s.Sync(ctx, keyset.EncodeInt64Cursor(1)); err == nil {
+		t.Fatalf("want error for a token of the wrong encoding")
+	}
+}
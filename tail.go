@@ -0,0 +1,190 @@
+package keyset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tailer follows rows that change over time in (updated_at, id) order, e.g.
+// to sync a table into a search index.
+//
+// A plain keyset window over updated_at misses rows whose transaction commits
+// after a row with a later updated_at has been read. Tailer guards against
+// that in two ways: it never reads rows newer than now minus Lag, and every
+// poll re-reads the last Overlap before its cursor, skipping rows it already
+// emitted (same id and updated_at).
+//
+// Delivery is at-least-once: after a restart the overlap is emitted again.
+// A Tailer is not safe for concurrent use.
+type Tailer[T any] struct {
+	// Fetch loads a page in ascending (updated_at, id) order, e.g. ksql.Fetch
+	// over ksql.QueryByTimeAndID. It must honor Page.Cursor, Bound and Until,
+	// including partial (time-only) cursors.
+	Fetch FetchFunc[T]
+	// Key returns the updated_at and id of an item.
+	Key func(item T) (time.Time, int64)
+
+	Store CheckpointStore // Optional; persists the cursor under Name
+	Name  string          // Checkpoint key
+
+	Lag      time.Duration    // Rows newer than now-Lag are left for a later poll
+	Overlap  time.Duration    // Window before the cursor that is re-read on every poll
+	Interval time.Duration    // Pause between polls in Run; defaults to one second
+	Limit    int              // Page size
+	Now      func() time.Time // Defaults to time.Now
+
+	cursor string
+	loaded bool
+	seen   map[int64]time.Time // ids emitted within the overlap, by updated_at
+}
+
+// Poll reads every change available now, calls fn for each non-empty batch
+// and then advances the cursor (saving it to Store, if set).
+func (t *Tailer[T]) Poll(ctx context.Context, fn func(ctx context.Context, batch []T) error) error {
+	if err := t.load(ctx); err != nil {
+		return err
+	}
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+
+	p := Page{
+		Limit:      t.Limit,
+		Until:      EncodeTimePrefixCursor(now().Add(-t.Lag)),
+		UntilBound: Inclusive,
+	}
+	if t.cursor != "" {
+		tm, _, err := DecodeTimeAndInt64Cursor(t.cursor)
+		if err != nil {
+			return fmt.Errorf("keyset: tail %q: %w", t.Name, err)
+		}
+		p.Cursor, p.Bound = EncodeTimePrefixCursor(tm.Add(-t.Overlap)), Inclusive
+	}
+	cursor := func(item T) string {
+		return EncodeTimeAndInt64Cursor(t.Key(item))
+	}
+
+	for items, err := range Pages(ctx, p, t.Fetch, cursor) {
+		if err != nil {
+			return err
+		}
+		batch := make([]T, 0, len(items))
+		for _, item := range items {
+			tm, id := t.Key(item)
+			if seen, ok := t.seen[id]; ok && seen.Equal(tm) {
+				continue
+			}
+			batch = append(batch, item)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		if err := fn(ctx, batch); err != nil {
+			return err
+		}
+		for _, item := range batch {
+			tm, id := t.Key(item)
+			t.seen[id] = tm
+			if c := cursor(item); t.cursor == "" || compareKey(c, t.cursor) > 0 {
+				t.cursor = c
+			}
+		}
+		if t.Store != nil {
+			if err := t.Store.Save(ctx, t.Name, t.cursor); err != nil {
+				return fmt.Errorf("keyset: save checkpoint %q: %w", t.Name, err)
+			}
+		}
+	}
+	t.prune()
+	return nil
+}
+
+// Run polls until ctx is done, pausing Interval between polls, and returns
+// the first error from Poll. Cancellation ends Run with a nil error.
+func (t *Tailer[T]) Run(ctx context.Context, fn func(ctx context.Context, batch []T) error) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.Poll(ctx, fn); err != nil {
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Changes runs the tailer in a goroutine and delivers every changed item on
+// the returned channel, which is closed when Run ends. The error channel
+// receives Run's result (nil after cancellation) and is then closed.
+// Items are checkpointed once they are handed to the channel.
+func (t *Tailer[T]) Changes(ctx context.Context) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		errc <- t.Run(ctx, func(ctx context.Context, batch []T) error {
+			for _, item := range batch {
+				select {
+				case out <- item:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}()
+	return out, errc
+}
+
+// load restores the cursor from Store on first use.
+func (t *Tailer[T]) load(ctx context.Context) error {
+	if t.seen == nil {
+		t.seen = map[int64]time.Time{}
+	}
+	if t.loaded || t.Store == nil {
+		return nil
+	}
+	c, err := t.Store.Load(ctx, t.Name)
+	if err != nil {
+		return fmt.Errorf("keyset: load checkpoint %q: %w", t.Name, err)
+	}
+	t.cursor, t.loaded = c, true
+	return nil
+}
+
+// prune forgets emitted ids that fell out of the overlap window.
+func (t *Tailer[T]) prune() {
+	if t.cursor == "" {
+		return
+	}
+	tm, _, err := DecodeTimeAndInt64Cursor(t.cursor)
+	if err != nil {
+		return
+	}
+	horizon := tm.Add(-t.Overlap)
+	for id, seen := range t.seen {
+		if seen.Before(horizon) {
+			delete(t.seen, id)
+		}
+	}
+}
+
+// compareKey compares two valid cursors of the same encoding.
+func compareKey(a, b string) int {
+	c, _ := CompareCursors(a, b)
+	return c
+}
@@ -0,0 +1,163 @@
+package keyset_test
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+type change struct {
+	ID        int64
+	UpdatedAt time.Time
+	Deleted   bool
+}
+
+// changeTable is an in-memory table served in ascending (updated_at, id)
+// order, honoring full and partial cursors, Bound and an inclusive Until.
+type changeTable struct {
+	mu      sync.Mutex
+	rows    map[int64]time.Time
+	deleted map[int64]bool
+}
+
+func (c *changeTable) put(id int64, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rows[id] = at
+}
+
+func (c *changeTable) fetch(_ context.Context, p keyset.Page) ([]change, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p.EnsureDefaults()
+
+	var all []change
+	for id, at := range c.rows {
+		all = append(all, change{ID: id, UpdatedAt: at, Deleted: c.deleted[id]})
+	}
+	key := func(ch change) string { return keyset.EncodeTimeAndInt64Cursor(ch.UpdatedAt, ch.ID) }
+	slices.SortFunc(all, func(a, b change) int {
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	var out []change
+	for _, ch := range all {
+		if p.Cursor != "" {
+			tm, _, full, _ := keyset.DecodeTimeAndInt64PrefixCursor(p.Cursor)
+			if full {
+				if c, _ := keyset.CompareCursors(key(ch), p.Cursor); c < 0 || (c == 0 && p.Bound == keyset.Exclusive) {
+					continue
+				}
+			} else if ch.UpdatedAt.Before(tm) {
+				continue
+			}
+		}
+		if p.Until != "" {
+			if tm, _, _, _ := keyset.DecodeTimeAndInt64PrefixCursor(p.Until); ch.UpdatedAt.After(tm) {
+				continue
+			}
+		}
+		if len(out) == p.Limit {
+			break
+		}
+		out = append(out, ch)
+	}
+	return out, nil
+}
+
+func TestTailer_LagOverlapAndDedupe(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	table := &changeTable{rows: map[int64]time.Time{1: at(10), 2: at(20)}}
+	now := at(100)
+	store := keyset.NewMemoryCheckpointStore()
+	tl := &keyset.Tailer[change]{
+		Fetch:   table.fetch,
+		Key:     func(ch change) (time.Time, int64) { return ch.UpdatedAt, ch.ID },
+		Store:   store,
+		Name:    "search-index",
+		Lag:     30 * time.Second,
+		Overlap: 10 * time.Second,
+		Limit:   2,
+		Now:     func() time.Time { return now },
+	}
+
+	var got []int64
+	poll := func() []int64 {
+		t.Helper()
+		got = got[:0]
+		err := tl.Poll(ctx, func(_ context.Context, batch []change) error {
+			for _, ch := range batch {
+				got = append(got, ch.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+		return got
+	}
+
+	if ids := poll(); !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("first poll want [1 2], got %v", ids)
+	}
+
+	// A transaction that started earlier commits late, behind the cursor but
+	// within the overlap; a fresh row is still inside the lag window.
+	table.put(3, at(15))
+	table.put(4, at(80))
+	if ids := poll(); !slices.Equal(ids, []int64{3}) {
+		t.Fatalf("second poll want [3], got %v", ids)
+	}
+
+	// Time passes: row 4 leaves the lag window; row 1 is updated again.
+	now = at(150)
+	table.put(1, at(110))
+	if ids := poll(); !slices.Equal(ids, []int64{4, 1}) {
+		t.Fatalf("third poll want [4 1], got %v", ids)
+	}
+	if ids := poll(); len(ids) != 0 {
+		t.Fatalf("idle poll must not emit, got %v", ids)
+	}
+
+	if stored, _ := store.Load(ctx, "search-index"); stored != keyset.EncodeTimeAndInt64Cursor(at(110), 1) {
+		t.Fatalf("checkpoint must hold the newest change, got %q", stored)
+	}
+}
+
+func TestTailer_Changes(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	table := &changeTable{rows: map[int64]time.Time{1: base, 2: base.Add(time.Second)}}
+	tl := &keyset.Tailer[change]{
+		Fetch:    table.fetch,
+		Key:      func(ch change) (time.Time, int64) { return ch.UpdatedAt, ch.ID },
+		Interval: time.Millisecond,
+	}
+
+	changes, errc := tl.Changes(ctx)
+	var got []int64
+	for ch := range changes {
+		got = append(got, ch.ID)
+		if len(got) == 2 {
+			cancel()
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, []int64{1, 2}) {
+		t.Fatalf("want [1 2], got %v", got)
+	}
+}
@@ -0,0 +1,157 @@
+package keyset
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Segment is a contiguous run of loaded items of a list, identified by the
+// cursors of its first and last item in display order.
+type Segment struct {
+	Start string
+	End   string
+}
+
+// Gap is a part of a list between two loaded segments that has not been
+// loaded yet, e.g. after returning to a timeline and loading its newest page.
+type Gap struct {
+	After  string // End of the segment displayed above the gap
+	Before string // Start of the segment displayed below the gap
+}
+
+// Next returns the bounded page that fills the gap from the top,
+// continuing after g.After and stopping before g.Before.
+func (g Gap) Next(limit int) Page {
+	return Page{Cursor: g.After, Dir: DirNext, Until: g.Before, Limit: limit}
+}
+
+// Prev returns the bounded page that fills the gap from the bottom,
+// continuing before g.Before and stopping after g.After.
+func (g Gap) Prev(limit int) Page {
+	return Page{Cursor: g.Before, Dir: DirPrev, Until: g.After, Limit: limit}
+}
+
+// Timeline tracks the segments of a list a client has loaded, for
+// Twitter-style timelines that load the newest page and older pages lazily.
+// Segments merge as they connect; what remains between them are Gaps.
+//
+// A Timeline is client state: persist Segments and restore it with NewTimeline.
+// It is not safe for concurrent use.
+type Timeline struct {
+	ord  Order
+	segs []Segment // disjoint, in display order
+}
+
+// NewTimeline returns a Timeline for a list displayed in ord, starting with
+// the given segments.
+func NewTimeline(ord Order, segs ...Segment) (*Timeline, error) {
+	tl := &Timeline{ord: ord}
+	for _, s := range segs {
+		if err := tl.insert(s.Start, s.End); err != nil {
+			return nil, err
+		}
+	}
+	return tl, nil
+}
+
+// Segments returns the loaded segments in display order.
+func (tl *Timeline) Segments() []Segment {
+	return slices.Clone(tl.segs)
+}
+
+// Gaps returns the unloaded stretches between segments in display order.
+func (tl *Timeline) Gaps() []Gap {
+	var gaps []Gap
+	for i := 1; i < len(tl.segs); i++ {
+		gaps = append(gaps, Gap{After: tl.segs[i-1].End, Before: tl.segs[i].Start})
+	}
+	return gaps
+}
+
+// Add records a loaded page: p is the page that was requested, start and end
+// the cursors of its first and last item in display order ("" when empty),
+// and more whether more items exist beyond the page in its direction
+// (HasNext for DirNext, HasPrev for DirPrev).
+//
+// A page read from a cursor connects to the cursor item, and a bounded page
+// (see Gap.Next and Gap.Prev) that did not fill up also connects to Until.
+func (tl *Timeline) Add(p Page, start, end string, more bool) error {
+	p.EnsureDefaults()
+	lo, hi := start, end
+	switch p.Dir {
+	case DirNext:
+		if p.Cursor != "" {
+			lo = p.Cursor
+			hi = cmp.Or(hi, p.Cursor)
+		}
+		if !more && p.Until != "" {
+			hi = p.Until
+			lo = cmp.Or(lo, p.Until)
+		}
+	case DirPrev:
+		if p.Cursor != "" {
+			hi = p.Cursor
+			lo = cmp.Or(lo, p.Cursor)
+		}
+		if !more && p.Until != "" {
+			lo = p.Until
+			hi = cmp.Or(hi, p.Until)
+		}
+	}
+	if lo == "" {
+		return nil
+	}
+	return tl.insert(lo, hi)
+}
+
+// AddResult records the result r of page p (see Timeline.Add).
+func AddResult[T any](tl *Timeline, p Page, r Result[T]) error {
+	p.EnsureDefaults()
+	more := r.HasNext
+	if p.Dir.Backward() {
+		more = r.HasPrev
+	}
+	return tl.Add(p, r.PrevCursor, r.NextCursor, more)
+}
+
+// insert adds the segment [lo, hi] and merges it with every segment it
+// overlaps or touches.
+func (tl *Timeline) insert(lo, hi string) error {
+	c, err := CompareCursors(lo, hi)
+	if err != nil {
+		return err
+	}
+	if tl.ord == Descending {
+		c = -c
+	}
+	if c > 0 {
+		lo, hi = hi, lo
+	}
+	// Cursors are valid from here on.
+	before := func(a, b string) int {
+		c, _ := CompareCursors(a, b)
+		if tl.ord == Descending {
+			return -c
+		}
+		return c
+	}
+
+	merged := Segment{Start: lo, End: hi}
+	segs := tl.segs[:0:0]
+	for _, s := range tl.segs {
+		if before(s.End, merged.Start) < 0 || before(merged.End, s.Start) < 0 {
+			segs = append(segs, s)
+			continue
+		}
+		if before(s.Start, merged.Start) < 0 {
+			merged.Start = s.Start
+		}
+		if before(s.End, merged.End) > 0 {
+			merged.End = s.End
+		}
+	}
+	segs = append(segs, merged)
+	slices.SortFunc(segs, func(a, b Segment) int { return before(a.Start, b.Start) })
+	tl.segs = segs
+	return nil
+}
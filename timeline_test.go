@@ -0,0 +1,103 @@
+package keyset_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestCompareCursors(t *testing.T) {
+	t.Parallel()
+	ts := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{keyset.EncodeInt64Cursor(-5), keyset.EncodeInt64Cursor(3), -1},
+		{keyset.EncodeInt64Cursor(7), keyset.EncodeInt64Cursor(7), 0},
+		{keyset.EncodeTimeAndInt64Cursor(ts, 2), keyset.EncodeTimeAndInt64Cursor(ts, 1), 1},
+		{keyset.EncodeTimeAndInt64Cursor(ts, 9), keyset.EncodeTimeAndInt64Cursor(ts.Add(time.Nanosecond), 1), -1},
+		{keyset.EncodeTimePrefixCursor(ts), keyset.EncodeTimeAndInt64Cursor(ts, 1), -1},
+	}
+	for _, c := range cases {
+		got, err := keyset.CompareCursors(c.a, c.b)
+		if err != nil || got != c.want {
+			t.Fatalf("compare(%q, %q): want %d, got (%d, %v)", c.a, c.b, c.want, got, err)
+		}
+	}
+	if _, err := keyset.CompareCursors("@@@", keyset.EncodeInt64Cursor(1)); err == nil {
+		t.Fatalf("want error for an invalid cursor")
+	}
+}
+
+func TestTimeline_GapsCloseAsSegmentsConnect(t *testing.T) {
+	t.Parallel()
+	cur := keyset.EncodeInt64Cursor
+	tl, err := keyset.NewTimeline(keyset.Descending)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	add := func(p keyset.Page, start, end int64, more bool) {
+		t.Helper()
+		if err := tl.Add(p, cur(start), cur(end), more); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+
+	// First visit: newest page and one older page connect.
+	add(keyset.Page{Dir: keyset.DirFirst, Limit: 10}, 100, 91, true)
+	add(keyset.Page{Cursor: cur(91), Limit: 10}, 90, 81, true)
+	if want := []keyset.Segment{{Start: cur(100), End: cur(81)}}; !slices.Equal(tl.Segments(), want) {
+		t.Fatalf("segments mismatch: %v", tl.Segments())
+	}
+
+	// Return later: the newest page leaves a gap above the old segment.
+	add(keyset.Page{Dir: keyset.DirFirst, Limit: 10}, 120, 111, true)
+	gaps := tl.Gaps()
+	if want := []keyset.Gap{{After: cur(111), Before: cur(100)}}; !slices.Equal(gaps, want) {
+		t.Fatalf("gaps mismatch: %v", gaps)
+	}
+
+	// Fill from the top: a full page shrinks the gap.
+	next := gaps[0].Next(5)
+	if next.Cursor != cur(111) || next.Until != cur(100) || next.Dir != keyset.DirNext {
+		t.Fatalf("unexpected fill page: %+v", next)
+	}
+	add(next, 110, 106, true)
+	if want := []keyset.Gap{{After: cur(106), Before: cur(100)}}; !slices.Equal(tl.Gaps(), want) {
+		t.Fatalf("gap must shrink: %v", tl.Gaps())
+	}
+
+	// Fill from the bottom: reaching Until closes the gap.
+	add(tl.Gaps()[0].Prev(10), 105, 101, false)
+	if want := []keyset.Segment{{Start: cur(120), End: cur(81)}}; !slices.Equal(tl.Segments(), want) || len(tl.Gaps()) != 0 {
+		t.Fatalf("segments must merge: %v", tl.Segments())
+	}
+}
+
+func TestTimeline_EmptyBoundedPageClosesGap(t *testing.T) {
+	t.Parallel()
+	cur := keyset.EncodeInt64Cursor
+
+	tl, err := keyset.NewTimeline(keyset.Ascending,
+		keyset.Segment{Start: cur(1), End: cur(5)},
+		keyset.Segment{Start: cur(9), End: cur(12)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := keyset.Result[int64]{}
+	if err := keyset.AddResult(tl, tl.Gaps()[0].Next(10), res); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if want := []keyset.Segment{{Start: cur(1), End: cur(12)}}; !slices.Equal(tl.Segments(), want) {
+		t.Fatalf("segments must merge: %v", tl.Segments())
+	}
+
+	if _, err := keyset.NewTimeline(keyset.Ascending, keyset.Segment{Start: "@@@", End: cur(1)}); err == nil {
+		t.Fatalf("want error for an invalid segment")
+	}
+}
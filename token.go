@@ -0,0 +1,126 @@
+package keyset
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ErrNotToken is returned by DecodeToken for strings that are not tokens,
+// such as plain key cursors.
+var ErrNotToken = errors.New("keyset: not a page token")
+
+const (
+	tokenMagic   = 'K'
+	tokenVersion = 1
+	// tokenHeader is magic, version, dir, flags, limit (uint32) and key length.
+	tokenHeader = 9
+	// tokenSnapshot flags a trailing snapshot key (length byte + key).
+	tokenSnapshot = 1 << 0
+)
+
+// Token is a self-describing cursor: a key cursor together with the direction
+// (and optionally the limit) of the page it continues. Clients can send it
+// back as the only pagination parameter; Page.EnsureDefaults unwraps it.
+type Token struct {
+	Cursor   string // Key cursor, e.g. from EncodeTimeAndInt64Cursor
+	Dir      Dir    // Direction of the page the token continues
+	Limit    int    // Optional page size; 0 leaves it to the request
+	Snapshot string // Optional snapshot bound carried across pages (see Page.Snapshot)
+}
+
+// NextToken returns a token continuing after cursor with DirNext.
+func NextToken(cursor string, limit int) string {
+	return Token{Cursor: cursor, Dir: DirNext, Limit: limit}.Encode()
+}
+
+// PrevToken returns a token continuing before cursor with DirPrev.
+func PrevToken(cursor string, limit int) string {
+	return Token{Cursor: cursor, Dir: DirPrev, Limit: limit}.Encode()
+}
+
+// Encode returns the token as an opaque base64url string. A token with
+// neither Cursor nor Snapshot encodes to ""; one with only a Snapshot encodes
+// an empty key, so it starts from the first page of the pinned view. Cursors
+// that are not valid base64url key cursors cannot be wrapped and are returned
+// unchanged.
+func (t Token) Encode() string {
+	if t.Cursor == "" && t.Snapshot == "" {
+		return ""
+	}
+	key, err := base64.RawURLEncoding.DecodeString(t.Cursor)
+	if err != nil || len(key) > math.MaxUint8 {
+		return t.Cursor
+	}
+	var snap []byte
+	if t.Snapshot != "" {
+		snap, err = base64.RawURLEncoding.DecodeString(t.Snapshot)
+		if err != nil || len(snap) > math.MaxUint8 {
+			return t.Cursor
+		}
+	}
+	limit := uint32(0)
+	if t.Limit > 0 && uint64(t.Limit) <= math.MaxUint32 {
+		limit = uint32(t.Limit)
+	}
+
+	b := make([]byte, tokenHeader, tokenHeader+len(key)+1+len(snap))
+	b[0], b[1], b[2] = tokenMagic, tokenVersion, byte(t.Dir)
+	binary.BigEndian.PutUint32(b[4:8], limit)
+	b[8] = byte(len(key))
+	b = append(b, key...)
+	if snap != nil {
+		b[3] |= tokenSnapshot
+		b = append(b, byte(len(snap)))
+		b = append(b, snap...)
+	}
+	if isKeyLength(len(b)) {
+		return t.Cursor
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeToken parses a string produced by Token.Encode.
+// It returns ErrNotToken for anything else, including plain key cursors.
+func DecodeToken(s string) (Token, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) < tokenHeader || isKeyLength(len(b)) || b[0] != tokenMagic || b[1] != tokenVersion {
+		return Token{}, ErrNotToken
+	}
+	t := Token{
+		Dir:   Dir(b[2]),
+		Limit: int(binary.BigEndian.Uint32(b[4:8])),
+	}
+	key, rest, ok := cutKey(b[8:])
+	if !ok {
+		return Token{}, ErrNotToken
+	}
+	t.Cursor = base64.RawURLEncoding.EncodeToString(key)
+	if b[3]&tokenSnapshot != 0 {
+		var snap []byte
+		if snap, rest, ok = cutKey(rest); !ok {
+			return Token{}, ErrNotToken
+		}
+		t.Snapshot = base64.RawURLEncoding.EncodeToString(snap)
+	}
+	if len(rest) != 0 {
+		return Token{}, ErrNotToken
+	}
+	return t, nil
+}
+
+// cutKey splits a length-prefixed key off the front of b.
+func cutKey(b []byte) (key, rest []byte, ok bool) {
+	if len(b) == 0 || len(b) < 1+int(b[0]) {
+		return nil, nil, false
+	}
+	n := 1 + int(b[0])
+	return b[1:n], b[n:], true
+}
+
+// isKeyLength reports whether n bytes could be a plain key cursor,
+// which a token must never be mistaken for.
+func isKeyLength(n int) bool {
+	return n == 8 || n == 16
+}
@@ -0,0 +1,120 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mickamy/go-keyset"
+)
+
+func TestToken_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cases := []keyset.Token{
+		{Cursor: keyset.EncodeInt64Cursor(42), Dir: keyset.DirNext},
+		{Cursor: keyset.EncodeTimeAndInt64Cursor(time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC), 7), Dir: keyset.DirPrev, Limit: 25},
+		{Dir: keyset.DirNext, Snapshot: keyset.EncodeInt64Cursor(9)},
+	}
+	for _, want := range cases {
+		got, err := keyset.DecodeToken(want.Encode())
+		if err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if got != want {
+			t.Fatalf("round trip mismatch: want %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestDecodeToken_RejectsPlainCursors(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{
+		"",
+		"@@@",
+		keyset.EncodeInt64Cursor(42),
+		keyset.EncodeTimeAndInt64Cursor(time.Now(), 1),
+	} {
+		if _, err := keyset.DecodeToken(s); !errors.Is(err, keyset.ErrNotToken) {
+			t.Fatalf("want ErrNotToken for %q, got %v", s, err)
+		}
+	}
+	if tok := (keyset.Token{Cursor: "not base64!", Dir: keyset.DirNext}).Encode(); tok != "not base64!" {
+		t.Fatalf("invalid cursors must be returned unchanged, got %q", tok)
+	}
+	if tok := (keyset.Token{Dir: keyset.DirNext}).Encode(); tok != "" {
+		t.Fatalf("an empty token must encode to \"\", got %q", tok)
+	}
+}
+
+func TestPage_EnsureDefaultsKeepsSnapshotWithoutCursor(t *testing.T) {
+	t.Parallel()
+
+	snap := keyset.EncodeInt64Cursor(9)
+	p := keyset.Page{Cursor: keyset.Token{Dir: keyset.DirNext, Limit: 5, Snapshot: snap}.Encode()}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.EnsureDefaults()
+	if p.Cursor != "" || p.Dir != keyset.DirNext || p.Limit != 5 || p.Snapshot != snap {
+		t.Fatalf("want the first page of the snapshot, got %+v", p)
+	}
+}
+
+func TestPage_EnsureDefaultsUnwrapsToken(t *testing.T) {
+	t.Parallel()
+	cur := keyset.EncodeInt64Cursor(10)
+
+	p := keyset.Page{Cursor: keyset.PrevToken(cur, 20)}
+	p.EnsureDefaults()
+	if p.Cursor != cur || p.Dir != keyset.DirPrev || p.Limit != 20 {
+		t.Fatalf("token not applied: %+v", p)
+	}
+
+	// Explicit fields take precedence over the token.
+	p = keyset.Page{Cursor: keyset.PrevToken(cur, 20), Dir: keyset.DirNext, Limit: 5}
+	p.EnsureDefaults()
+	if p.Cursor != cur || p.Dir != keyset.DirNext || p.Limit != 5 {
+		t.Fatalf("explicit fields overridden: %+v", p)
+	}
+	if err := (keyset.Page{Cursor: keyset.PrevToken(cur, 0), Dir: keyset.DirNext}).Validate(); !errors.Is(err, keyset.ErrInvalidPage) {
+		t.Fatalf("want direction mismatch reported, got %v", err)
+	}
+}
+
+func TestPaginate_FollowsTokens(t *testing.T) {
+	t.Parallel()
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7}
+	calls := 0
+	fetch := memFetch(ids, &calls)
+	ctx := context.Background()
+
+	first, err := keyset.Paginate(ctx, keyset.Page{Limit: 3}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.PrevToken(3) != "" {
+		t.Fatalf("first page must not have a prev token")
+	}
+
+	// Only the token is sent back: direction and limit come from it.
+	second, err := keyset.Paginate(ctx, keyset.Page{Cursor: first.NextToken(3)}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(second.Items, []int64{4, 5, 6}) {
+		t.Fatalf("next page mismatch: %v", second.Items)
+	}
+
+	back, err := keyset.Paginate(ctx, keyset.Page{Cursor: second.PrevToken(3)}, fetch, keyset.EncodeInt64Cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(back.Items, []int64{1, 2, 3}) || back.HasPrev {
+		t.Fatalf("prev page mismatch: %+v", back)
+	}
+}
@@ -0,0 +1,121 @@
+package keyset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// RangeFetchFunc loads a single page for p restricted to the key range r and
+// returns its items in display order.
+type RangeFetchFunc[T any] func(ctx context.Context, r Int64Range, p Page) ([]T, error)
+
+// Progress is a snapshot of a Walker run, reported after every page and range.
+type Progress struct {
+	Ranges     int   // Total number of ranges
+	RangesDone int   // Ranges finished, successfully or not
+	Failed     int   // Ranges that stopped with an error
+	Rows       int64 // Rows handled so far across all ranges
+}
+
+// RangeError records the failure of a single range.
+type RangeError struct {
+	Range Int64Range
+	Err   error
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("keyset: range [%d, %d]: %v", e.Range.Min, e.Range.Max, e.Err)
+}
+
+func (e *RangeError) Unwrap() error { return e.Err }
+
+// Walker processes key ranges concurrently. Every range is paged through with
+// its own cursor, so a range behaves like a bounded keyset window.
+type Walker[T any] struct {
+	Fetch      RangeFetchFunc[T] // Loads a page within a range
+	Cursor     CursorFunc[T]     // Encodes the cursor of an item
+	Page       Page              // Initial page of every range (Limit/Dir)
+	Workers    int               // Number of concurrent ranges; defaults to 1
+	OnProgress func(Progress)    // Optional; called serially
+}
+
+// Walk runs fn for every page of every range using a pool of w.Workers workers.
+//
+// A failing range does not stop the others; its error is wrapped in a
+// *RangeError and all failures are returned joined. Cancelling ctx stops the
+// walk after the pages in flight.
+func (w Walker[T]) Walk(ctx context.Context, ranges []Int64Range, fn func(ctx context.Context, r Int64Range, batch []T) error) error {
+	workers := w.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		progress = Progress{Ranges: len(ranges)}
+		errs     []error
+	)
+	report := func(update func(*Progress)) {
+		mu.Lock()
+		defer mu.Unlock()
+		update(&progress)
+		if w.OnProgress != nil {
+			w.OnProgress(progress)
+		}
+	}
+
+	jobs := make(chan Int64Range)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				err := w.walkRange(ctx, r, fn, func(n int) {
+					report(func(p *Progress) { p.Rows += int64(n) })
+				})
+				report(func(p *Progress) {
+					p.RangesDone++
+					if err != nil {
+						p.Failed++
+						errs = append(errs, &RangeError{Range: r, Err: err})
+					}
+				})
+			}
+		}()
+	}
+
+feed:
+	for _, r := range ranges {
+		select {
+		case jobs <- r:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (w Walker[T]) walkRange(ctx context.Context, r Int64Range, fn func(context.Context, Int64Range, []T) error, done func(n int)) error {
+	fetch := func(ctx context.Context, p Page) ([]T, error) {
+		return w.Fetch(ctx, r, p)
+	}
+	for batch, err := range Pages(ctx, w.Page, fetch, w.Cursor) {
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, r, batch); err != nil {
+			return err
+		}
+		done(len(batch))
+	}
+	return nil
+}
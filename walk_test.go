@@ -0,0 +1,109 @@
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mickamy/go-keyset"
+)
+
+// rangeFetch serves ids (sorted ascending) restricted to each range.
+func rangeFetch(ids []int64) keyset.RangeFetchFunc[int64] {
+	return func(ctx context.Context, r keyset.Int64Range, p keyset.Page) ([]int64, error) {
+		var within []int64
+		for _, id := range ids {
+			if r.Contains(id) {
+				within = append(within, id)
+			}
+		}
+		calls := 0
+		return memFetch(within, &calls)(ctx, p)
+	}
+}
+
+func TestWalker_ProcessesEveryRange(t *testing.T) {
+	t.Parallel()
+
+	var ids []int64
+	for i := int64(1); i <= 100; i++ {
+		ids = append(ids, i)
+	}
+
+	var (
+		mu       sync.Mutex
+		seen     []int64
+		progress []keyset.Progress
+	)
+	w := keyset.Walker[int64]{
+		Fetch:   rangeFetch(ids),
+		Cursor:  keyset.EncodeInt64Cursor,
+		Page:    keyset.Page{Limit: 7},
+		Workers: 3,
+		OnProgress: func(p keyset.Progress) {
+			progress = append(progress, p)
+		},
+	}
+	err := w.Walk(context.Background(), keyset.SplitInt64(1, 100, 4), func(_ context.Context, r keyset.Int64Range, batch []int64) error {
+		for _, id := range batch {
+			if !r.Contains(id) {
+				t.Errorf("id %d outside range %v", id, r)
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slices.Sort(seen)
+	if !slices.Equal(seen, ids) {
+		t.Fatalf("every id must be seen exactly once, got %v", seen)
+	}
+	last := progress[len(progress)-1]
+	if last != (keyset.Progress{Ranges: 4, RangesDone: 4, Rows: 100}) {
+		t.Fatalf("unexpected final progress: %+v", last)
+	}
+}
+
+func TestWalker_AggregatesRangeErrors(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+	ranges := keyset.SplitInt64(1, 40, 4)
+	w := keyset.Walker[int64]{
+		Fetch: func(ctx context.Context, r keyset.Int64Range, p keyset.Page) ([]int64, error) {
+			if r.Min == 11 {
+				return nil, boom
+			}
+			return rangeFetch([]int64{1, 15, 25, 35})(ctx, r, p)
+		},
+		Cursor:  keyset.EncodeInt64Cursor,
+		Workers: 2,
+	}
+
+	var (
+		mu   sync.Mutex
+		seen []int64
+	)
+	err := w.Walk(context.Background(), ranges, func(_ context.Context, _ keyset.Int64Range, batch []int64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, batch...)
+		return nil
+	})
+
+	var re *keyset.RangeError
+	if !errors.As(err, &re) || re.Range != ranges[1] || !errors.Is(err, boom) {
+		t.Fatalf("want RangeError for %v wrapping boom, got %v", ranges[1], err)
+	}
+	slices.Sort(seen)
+	if !slices.Equal(seen, []int64{1, 25, 35}) {
+		t.Fatalf("other ranges must still be processed, got %v", seen)
+	}
+}